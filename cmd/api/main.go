@@ -1,18 +1,54 @@
 package main
 
 import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/natanchagas/gin-crud/cmd/api/server"
 	_ "github.com/natanchagas/gin-crud/config"
 )
 
 func main() {
-	app, err := server.NewApp()
+	os.Exit(run())
+}
+
+// run serves until SIGINT or SIGTERM and reports 0 after a clean shutdown, 1
+// when the app fails to start, serve or shut down. A second signal skips the
+// drain and kills the process.
+func run() int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	logger, err := server.NewLogger()
 	if err != nil {
-		panic(err)
+		log.Printf("configuring logs: %v", err)
+		return 1
 	}
 
-	err = app.Run()
+	// Libraries logging through the standard logger end up in the same
+	// records.
+	slog.SetDefault(logger)
+
+	app, err := server.NewApp(logger)
 	if err != nil {
-		panic(err)
+		logger.Error("starting", "error", err)
+		return 1
+	}
+
+	if err := app.Run(ctx); err != nil {
+		logger.Error("serving", "error", err)
+		return 1
 	}
+
+	logger.Info("shut down cleanly")
+	return 0
 }
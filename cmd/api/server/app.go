@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/http/healthhdlr"
+)
+
+// DefaultShutdownTimeout bounds how long Shutdown waits for in-flight
+// requests when App.ShutdownTimeout is zero.
+const DefaultShutdownTimeout = 15 * time.Second
+
+type App struct {
+	Server *http.Server
+	// DB is closed on shutdown, after the requests using it have drained.
+	// It is nil when the repositories need no database.
+	DB *sql.DB
+	// Health, when set, reports not ready as soon as shutdown starts.
+	Health          *healthhdlr.HealthHandler
+	ShutdownTimeout time.Duration
+	// ShutdownDelay keeps serving for a while after reporting not ready, so
+	// load balancers polling readiness stop routing here before the listener
+	// closes.
+	ShutdownDelay time.Duration
+
+	mu       sync.Mutex
+	hooks    []func(ctx context.Context) error
+	shutdown sync.Once
+	err      error
+}
+
+// OnShutdown registers hook to run once requests have drained and before the
+// database is closed. Hooks run in reverse order of registration.
+func (a *App) OnShutdown(hook func(ctx context.Context) error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.hooks = append(a.hooks, hook)
+}
+
+// Run listens on Server.Addr and serves until ctx is done, then shuts down.
+func (a *App) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", a.Server.Addr)
+	if err != nil {
+		return errors.Join(err, a.Shutdown(context.Background()))
+	}
+
+	return a.Serve(ctx, ln)
+}
+
+// Serve is Run on a listener of the caller's. It returns nil after a clean
+// shutdown, or why serving or shutting down failed.
+func (a *App) Serve(ctx context.Context, ln net.Listener) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- a.Server.Serve(ln)
+	}()
+
+	select {
+	case err := <-served:
+		// The server stopped on its own, so there is nothing left to drain.
+		return errors.Join(err, a.Shutdown(context.Background()))
+	case <-ctx.Done():
+	}
+
+	a.notReady()
+	time.Sleep(a.ShutdownDelay)
+
+	timeout := a.ShutdownTimeout
+	if timeout == 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	// ctx is already done, so the drain gets a deadline of its own.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := a.Shutdown(shutdownCtx)
+	if served := <-served; !errors.Is(served, http.ErrServerClosed) {
+		err = errors.Join(served, err)
+	}
+
+	return err
+}
+
+// Shutdown stops accepting connections and waits, until ctx is done, for
+// in-flight requests. It then runs the shutdown hooks and closes DB even if
+// draining timed out. Later calls return the result of the first.
+func (a *App) Shutdown(ctx context.Context) error {
+	a.shutdown.Do(func() {
+		a.notReady()
+
+		errs := []error{a.Server.Shutdown(ctx)}
+
+		a.mu.Lock()
+		hooks := a.hooks
+		a.mu.Unlock()
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			errs = append(errs, hooks[i](ctx))
+		}
+
+		if a.DB != nil {
+			errs = append(errs, a.DB.Close())
+		}
+
+		a.err = errors.Join(errs...)
+	})
+
+	return a.err
+}
+
+func (a *App) notReady() {
+	if a.Health != nil {
+		a.Health.ShuttingDown()
+	}
+}
@@ -0,0 +1,156 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natanchagas/gin-crud/cmd/api/server"
+	"github.com/natanchagas/gin-crud/internal/adapters/http/healthhdlr"
+)
+
+// slowApp serves a handler that answers once release is closed, and signals
+// on started when a request arrives.
+func slowApp(t *testing.T, timeout time.Duration) (app *server.App, mock sqlmock.Sqlmock, started, release chan struct{}) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	started, release = make(chan struct{}), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "done")
+	})
+
+	app = &server.App{
+		Server:          &http.Server{Handler: handler},
+		DB:              db,
+		ShutdownTimeout: timeout,
+	}
+
+	return app, mock, started, release
+}
+
+func TestServe(t *testing.T) {
+	testCases := []struct {
+		name       string
+		timeout    time.Duration
+		release    bool
+		assertions func(t *testing.T, served error, body string, order []string)
+	}{
+		{
+			name:    "When in-flight requests finish in time, should answer them then run hooks and close the database",
+			timeout: time.Second,
+			release: true,
+			assertions: func(t *testing.T, served error, body string, order []string) {
+				assert.NoError(t, served)
+				assert.Equal(t, "done", body)
+				assert.Equal(t, []string{"second", "first"}, order)
+			},
+		},
+		{
+			name:    "When in-flight requests outlast the timeout, should still run hooks and close the database",
+			timeout: 50 * time.Millisecond,
+			assertions: func(t *testing.T, served error, body string, order []string) {
+				assert.ErrorIs(t, served, context.DeadlineExceeded)
+				assert.Equal(t, []string{"second", "first"}, order)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app, mock, started, release := slowApp(t, tc.timeout)
+			mock.ExpectClose()
+
+			var order []string
+			app.OnShutdown(func(ctx context.Context) error {
+				order = append(order, "first")
+				return nil
+			})
+			app.OnShutdown(func(ctx context.Context) error {
+				order = append(order, "second")
+				return nil
+			})
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			served := make(chan error, 1)
+			go func() { served <- app.Serve(ctx, ln) }()
+
+			body := make(chan string, 1)
+			go func() {
+				res, err := http.Get("http://" + ln.Addr().String())
+				if err != nil {
+					body <- ""
+					return
+				}
+				defer res.Body.Close()
+
+				b, _ := io.ReadAll(res.Body)
+				body <- string(b)
+			}()
+
+			<-started
+			cancel()
+
+			if tc.release {
+				// Give Shutdown time to stop accepting before the request ends.
+				time.Sleep(20 * time.Millisecond)
+				close(release)
+			}
+
+			err = <-served
+			if !tc.release {
+				close(release)
+			}
+
+			tc.assertions(t, err, <-body, order)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	app, mock, _, _ := slowApp(t, time.Second)
+	mock.ExpectClose()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	app.Health = healthhdlr.NewHealthHandler()
+	app.Health.BuildRoutes(router)
+
+	failure := errors.New("flush failed")
+	calls := 0
+	app.OnShutdown(func(ctx context.Context) error {
+		calls++
+		return failure
+	})
+
+	first := app.Shutdown(context.Background())
+	second := app.Shutdown(context.Background())
+
+	assert.ErrorIs(t, first, failure)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, calls, "hooks run once however many times Shutdown is called")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
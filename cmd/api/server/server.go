@@ -1,55 +1,229 @@
 package server
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	"github.com/natanchagas/gin-crud/internal/adapters/http/healthhdlr"
+	"github.com/natanchagas/gin-crud/internal/adapters/http/middleware"
 	"github.com/natanchagas/gin-crud/internal/adapters/http/realstatehdlr"
 	"github.com/natanchagas/gin-crud/internal/adapters/repository"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/migrate"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
 	"github.com/natanchagas/gin-crud/internal/core/service"
+	"github.com/natanchagas/gin-crud/internal/pkg/cursor"
+	"github.com/natanchagas/gin-crud/internal/pkg/logging"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type App struct {
-	Server *http.Server
-}
+const (
+	driverMySQL    = "mysql"
+	driverSQLite   = "sqlite"
+	driverPostgres = "postgres"
+	driverMemory   = "memory"
+)
 
-func NewApp() (*App, error) {
+// NewLogger returns the logger configured by log.level and log.format,
+// writing to standard output.
+func NewLogger() (*slog.Logger, error) {
+	return logging.New(os.Stdout, viper.GetString("log.level"), viper.GetString("log.format"))
+}
 
-	router := gin.Default()
+func NewApp(logger *slog.Logger) (*App, error) {
+	// Debug mode prints plain-text route and warning lines to standard
+	// output, between the structured log records.
+	gin.SetMode(gin.ReleaseMode)
 
-	db, err := initialiazeDatabase()
+	tp, stopTracing, err := newTracerProvider(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	rsr := repository.NewRealStateRepository(db)
-	rss := service.NewRealStateService(rsr)
-	rsh := realstatehdlr.NewRealStateHandler(rss)
+	// The database queries are traced through the global provider.
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	router := NewRouter(logger, tp, registry)
+
+	rsr, ar, db, err := openRepositories()
+	if err != nil {
+		return nil, errors.Join(err, stopTracing(context.Background()))
+	}
+
+	if db != nil {
+		registry.MustRegister(collectors.NewDBStatsCollector(db, viper.GetString("repository.driver")))
+	}
+	rsr = repository.NewInstrumentedRealStateRepository(rsr, registry)
+	rsr = repository.NewTracedRealStateRepository(rsr, tp)
+	ar = repository.NewTracedAuditRepository(ar, tp)
+
+	rss := service.NewRealStateService(rsr, ar)
+	rss.PurgeRetention = viper.GetDuration("realstate.purge_retention")
+	rsh := realstatehdlr.NewRealStateHandler(service.NewTracedRealStateService(rss, tp))
+	rsh.CursorSecret, err = cursorSecret(logger)
+	if err != nil {
+		return nil, errors.Join(err, stopTracing(context.Background()))
+	}
+	rsh.RequireIfMatch = viper.GetBool("rest.require_if_match")
 
 	rsh.BuildRoutes(router)
 
+	hh := healthhdlr.NewHealthHandler()
+	hh.CheckTimeout = viper.GetDuration("health.check_timeout")
+	if db != nil {
+		hh.Register("database", healthhdlr.CheckerFunc(db.PingContext))
+	}
+
+	hh.BuildRoutes(router)
+
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(registry, promhttp.HandlerOpts{})))
+
 	server := http.Server{
 		Addr:    fmt.Sprintf(":%d", viper.GetInt("rest.port")),
 		Handler: router,
 	}
 
-	return &App{
-		Server: &server,
-	}, nil
+	app := &App{
+		Server:          &server,
+		DB:              db,
+		Health:          hh,
+		ShutdownTimeout: viper.GetDuration("rest.shutdown_timeout"),
+		ShutdownDelay:   viper.GetDuration("rest.shutdown_delay"),
+	}
+	app.OnShutdown(stopTracing)
+
+	return app, nil
+}
+
+// NewRouter returns an engine running the middleware every route shares.
+// Metrics wraps Recovery so requests that panic are still counted, as the
+// 500 they are answered with.
+func NewRouter(logger *slog.Logger, tp trace.TracerProvider, registerer prometheus.Registerer) *gin.Engine {
+	router := gin.New()
+	router.Use(otelgin.Middleware(viper.GetString("tracing.service_name"), otelgin.WithTracerProvider(tp), otelgin.WithPropagators(propagator), otelgin.WithFilter(traced)))
+	router.Use(middleware.RequestID(viper.GetString("rest.request_id_header"), logger))
+	router.Use(middleware.AccessLog(), middleware.Metrics(registerer), middleware.Recovery())
+	router.Use(middleware.Identity(viper.GetString("rest.identity_header"), viper.GetString("rest.roles_header"), viper.GetBool("rest.trust_roles_header")))
+
+	return router
+}
+
+// cursorSecret returns rest.cursor_secret, or a random secret when it is not
+// set, so no cursor is ever signed with a secret published in the sample
+// configuration.
+func cursorSecret(logger *slog.Logger) ([]byte, error) {
+	if secret := viper.GetString("rest.cursor_secret"); secret != "" {
+		return []byte(secret), nil
+	}
+
+	logger.Warn("rest.cursor_secret is not set, so pagination cursors will not survive a restart or work across replicas")
+
+	return cursor.NewSecret()
 }
 
-func (a *App) Run() error {
-	return a.Server.ListenAndServe()
+// OpenDatabase connects to the database chosen by repository.driver.
+func OpenDatabase() (*sql.DB, error) {
+	switch driver := viper.GetString("repository.driver"); driver {
+	case "", driverMySQL:
+		return openMySQL()
+	case driverSQLite:
+		return repository.OpenSQLite(viper.GetString("sqlite.path"))
+	case driverPostgres:
+		return openPostgres()
+	case driverMemory:
+		return nil, errors.New("the memory driver has no database")
+	default:
+		return nil, fmt.Errorf("unknown repository driver %q", driver)
+	}
 }
 
-func initialiazeDatabase() (*sql.DB, error) {
+// NewMigrator returns a migrator with the schema migrations for
+// repository.driver. Databases created from the original tables script are
+// taken to be at the first migration, which creates the same table.
+func NewMigrator(db *sql.DB) (*migrate.Migrator, error) {
+	var (
+		m          *migrate.Migrator
+		migrations []migrate.Migration
+		err        error
+	)
+
+	switch viper.GetString("repository.driver") {
+	case driverSQLite:
+		migrations, err = migrate.SQLite()
+		m = migrate.NewSQLite(db, migrations)
+	case driverPostgres:
+		migrations, err = migrate.Postgres()
+		m = migrate.NewPostgres(db, migrations)
+	default:
+		migrations, err = migrate.MySQL()
+		m = migrate.New(db, migrations)
+	}
+
+	m.Baseline, m.BaselineTable = 1, "real_states"
+
+	return m, err
+}
+
+// openRepositories returns the repositories of repository.driver and their
+// database, migrated first when repository.auto_migrate is set. The memory
+// driver needs no database, so it returns a nil one, and starts empty on
+// every run.
+func openRepositories() (ports.RealStateRepository, ports.AuditRepository, *sql.DB, error) {
+	if viper.GetString("repository.driver") == driverMemory {
+		rsr := repository.NewMemoryRealStateRepository()
+		return rsr, repository.NewMemoryAuditRepository(rsr), nil, nil
+	}
+
+	db, err := OpenDatabase()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if viper.GetBool("repository.auto_migrate") {
+		if err := migrateDatabase(db); err != nil {
+			db.Close()
+			return nil, nil, nil, err
+		}
+	}
+
+	rsr, ar := newRepositories(db)
+
+	return rsr, ar, db, nil
+}
+
+func newRepositories(db *sql.DB) (ports.RealStateRepository, ports.AuditRepository) {
+	switch viper.GetString("repository.driver") {
+	case driverSQLite:
+		return repository.NewSQLiteRealStateRepository(db), repository.NewAuditRepository(db)
+	case driverPostgres:
+		return repository.NewPostgresRealStateRepository(db), repository.NewPostgresAuditRepository(db)
+	default:
+		return repository.NewRealStateRepository(db), repository.NewAuditRepository(db)
+	}
+}
+
+func openMySQL() (*sql.DB, error) {
 
 	cfg := mysql.Config{
 		User:      viper.GetString("mysql.username"),
@@ -57,9 +231,36 @@ func initialiazeDatabase() (*sql.DB, error) {
 		Addr:      fmt.Sprintf("%s:%d", viper.GetString("mysql.host"), viper.GetInt("mysql.port")),
 		DBName:    viper.GetString("mysql.database"),
 		ParseTime: true,
+		// Report matched rather than changed rows so an UPDATE that stores
+		// identical values is not mistaken for a missing real state.
+		ClientFoundRows: true,
+	}
+
+	db, err := repository.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetConnMaxLifetime(time.Minute)
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(10)
+
+	return db, db.Ping()
+
+}
+
+func openPostgres() (*sql.DB, error) {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(viper.GetString("postgres.username"), viper.GetString("postgres.password")),
+		Host:   fmt.Sprintf("%s:%d", viper.GetString("postgres.host"), viper.GetInt("postgres.port")),
+		Path:   viper.GetString("postgres.database"),
+		// A UTC session makes timestamps read back in UTC, as they are
+		// written.
+		RawQuery: url.Values{"sslmode": {viper.GetString("postgres.sslmode")}, "timezone": {"UTC"}}.Encode(),
 	}
 
-	db, err := sql.Open("mysql", cfg.FormatDSN())
+	db, err := repository.Open("postgres", dsn.String())
 	if err != nil {
 		return nil, err
 	}
@@ -69,5 +270,14 @@ func initialiazeDatabase() (*sql.DB, error) {
 	db.SetMaxIdleConns(10)
 
 	return db, db.Ping()
+}
+
+func migrateDatabase(db *sql.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
 
+	_, err = m.Up(context.Background())
+	return err
 }
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	exporterNone   = "none"
+	exporterStdout = "stdout"
+	exporterOTLP   = "otlp"
+)
+
+// propagator reads the trace of incoming requests from their traceparent
+// and baggage headers.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// untracedPaths are polled by the orchestrator and the monitoring system,
+// and would bury the traces of real requests.
+var untracedPaths = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// newTracerProvider returns a tracer provider sending spans to
+// tracing.exporter, along with the function that flushes and stops it. No
+// exporter gives a provider that records nothing, though the trace of an
+// incoming request is still propagated.
+func newTracerProvider(ctx context.Context) (trace.TracerProvider, func(context.Context) error, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch name := viper.GetString("tracing.exporter"); name {
+	case "", exporterNone:
+		return noop.NewTracerProvider(), func(context.Context) error { return nil }, nil
+	case exporterStdout:
+		exporter, err = stdouttrace.New()
+	case exporterOTLP:
+		var options []otlptracehttp.Option
+		if endpoint := viper.GetString("tracing.otlp.endpoint"); endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if viper.GetBool("tracing.otlp.insecure") {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", name)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(viper.GetString("tracing.service_name")),
+	))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ratio := 1.0
+	if viper.IsSet("tracing.sample_ratio") {
+		ratio = viper.GetFloat64("tracing.sample_ratio")
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+
+	return tp, tp.Shutdown, nil
+}
+
+func traced(r *http.Request) bool {
+	return !untracedPaths[r.URL.Path]
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/natanchagas/gin-crud/cmd/api/server"
+	_ "github.com/natanchagas/gin-crud/config"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/migrate"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up          apply every pending migration
+  down        revert the last applied migration
+  status      list migrations and whether they are applied
+  goto <n>    apply or revert migrations until version n; 0 reverts all
+  baseline <n>
+              record migrations up to version n as applied without running
+              them, for a database whose schema already has their changes
+`
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	var version uint64
+	switch args[0] {
+	case "up", "down", "status":
+		if len(args) != 1 {
+			fmt.Fprint(os.Stderr, usage)
+			return 2
+		}
+	case "goto", "baseline":
+		if len(args) != 2 {
+			fmt.Fprint(os.Stderr, usage)
+			return 2
+		}
+
+		v, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: version %q is not a non-negative integer\n", args[1])
+			return 2
+		}
+		version = v
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	db, err := server.OpenDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+	defer db.Close()
+
+	m, err := server.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+
+	ctx := context.Background()
+
+	var steps []migrate.Step
+	switch args[0] {
+	case "up":
+		steps, err = m.Up(ctx)
+	case "down":
+		steps, err = m.Down(ctx)
+	case "goto":
+		steps, err = m.Goto(ctx, version)
+	case "baseline":
+		steps, err = m.MarkApplied(ctx, version)
+	case "status":
+		return status(ctx, m)
+	}
+
+	for _, step := range steps {
+		fmt.Println(step)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+
+	if len(steps) == 0 {
+		fmt.Println("nothing to do")
+	}
+
+	return 0
+}
+
+func status(ctx context.Context, m *migrate.Migrator) int {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.Applied {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+
+	if err := w.Flush(); err != nil {
+		return 1
+	}
+
+	return 0
+}
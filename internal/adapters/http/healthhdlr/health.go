@@ -0,0 +1,153 @@
+package healthhdlr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCheckTimeout bounds each readiness check when
+// HealthHandler.CheckTimeout is zero.
+const DefaultCheckTimeout = 2 * time.Second
+
+const (
+	StatusOK           = "ok"
+	StatusReady        = "ready"
+	StatusNotReady     = "not_ready"
+	StatusShuttingDown = "shutting_down"
+	StatusUp           = "up"
+	StatusDown         = "down"
+)
+
+// Checker reports whether a dependency the app needs to serve requests is
+// usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc lets a plain function, such as (*sql.DB).PingContext, be used
+// as a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type checkResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type healthResponse struct {
+	Status string                   `json:"status"`
+	Checks map[string]checkResponse `json:"checks,omitempty"`
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+type HealthHandler struct {
+	CheckTimeout time.Duration
+
+	mu           sync.RWMutex
+	checkers     []namedChecker
+	shuttingDown atomic.Bool
+}
+
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+// Register adds a dependency that must pass its check for the app to be
+// ready, reported under name.
+func (h *HealthHandler) Register(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checkers = append(h.checkers, namedChecker{name: name, checker: checker})
+}
+
+// ShuttingDown makes the app report not ready from now on, so no new traffic
+// is routed to it while in-flight requests drain.
+func (h *HealthHandler) ShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// healthz only tells the process is alive and serving HTTP; dependencies
+// failing must not get it restarted.
+func (h *HealthHandler) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, healthResponse{Status: StatusOK})
+}
+
+// readyz runs every check at once and answers within CheckTimeout, counting
+// the checks still running by then as down.
+func (h *HealthHandler) readyz(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, healthResponse{Status: StatusShuttingDown})
+		return
+	}
+
+	h.mu.RLock()
+	checkers := h.checkers
+	h.mu.RUnlock()
+
+	timeout := h.CheckTimeout
+	if timeout == 0 {
+		timeout = DefaultCheckTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	type result struct {
+		index int
+		err   error
+	}
+
+	// Buffered so checks that ignore ctx and finish late do not block.
+	done := make(chan result, len(checkers))
+	for i, nc := range checkers {
+		go func(i int, checker Checker) {
+			done <- result{index: i, err: checker.Check(ctx)}
+		}(i, nc.checker)
+	}
+
+	results := make([]checkResponse, len(checkers))
+	for i := range results {
+		results[i] = checkResponse{Status: StatusDown, Error: context.DeadlineExceeded.Error()}
+	}
+
+collect:
+	for range checkers {
+		select {
+		case r := <-done:
+			results[r.index] = checkResponse{Status: StatusUp}
+			if r.err != nil {
+				results[r.index] = checkResponse{Status: StatusDown, Error: r.err.Error()}
+			}
+		case <-ctx.Done():
+			break collect
+		}
+	}
+
+	response, code := healthResponse{Status: StatusReady, Checks: map[string]checkResponse{}}, http.StatusOK
+	for i, nc := range checkers {
+		response.Checks[nc.name] = results[i]
+		if results[i].Status == StatusDown {
+			response.Status, code = StatusNotReady, http.StatusServiceUnavailable
+		}
+	}
+
+	c.JSON(code, response)
+}
+
+func (h *HealthHandler) BuildRoutes(router *gin.Engine) {
+	router.GET("/healthz", h.healthz)
+	router.GET("/readyz", h.readyz)
+}
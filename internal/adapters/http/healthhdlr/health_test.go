@@ -0,0 +1,107 @@
+package healthhdlr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/adapters/http/healthhdlr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	h := healthhdlr.NewHealthHandler()
+	h.Register("database", healthhdlr.CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+	h.BuildRoutes(router)
+
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestReadyz(t *testing.T) {
+	up := healthhdlr.CheckerFunc(func(ctx context.Context) error { return nil })
+	down := healthhdlr.CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+	hung := healthhdlr.CheckerFunc(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	testCases := []struct {
+		name         string
+		checkers     map[string]healthhdlr.Checker
+		shuttingDown bool
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "When there are no dependencies, should be ready",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status":"ready"}`,
+		},
+		{
+			name:         "When every dependency is up, should be ready with each status",
+			checkers:     map[string]healthhdlr.Checker{"database": up, "cache": up},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"status":"ready","checks":{"database":{"status":"up"},"cache":{"status":"up"}}}`,
+		},
+		{
+			name:         "When a dependency is down, should not be ready and report why",
+			checkers:     map[string]healthhdlr.Checker{"database": down, "cache": up},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `{"status":"not_ready","checks":{"database":{"status":"down","error":"connection refused"},"cache":{"status":"up"}}}`,
+		},
+		{
+			name:         "When a check outlasts the timeout, should report it down without waiting for it",
+			checkers:     map[string]healthhdlr.Checker{"database": hung},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `{"status":"not_ready","checks":{"database":{"status":"down","error":"context deadline exceeded"}}}`,
+		},
+		{
+			name:         "When shutting down, should not be ready",
+			checkers:     map[string]healthhdlr.Checker{"database": up},
+			shuttingDown: true,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: `{"status":"shutting_down"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+
+			h := healthhdlr.NewHealthHandler()
+			h.CheckTimeout = 50 * time.Millisecond
+			for name, checker := range tc.checkers {
+				h.Register(name, checker)
+			}
+			if tc.shuttingDown {
+				h.ShuttingDown()
+			}
+			h.BuildRoutes(router)
+
+			req, _ := http.NewRequest("GET", "/readyz", nil)
+			w := httptest.NewRecorder()
+
+			start := time.Now()
+			router.ServeHTTP(w, req)
+
+			assert.Less(t, time.Since(start), 500*time.Millisecond)
+			assert.Equal(t, tc.expectedCode, w.Code)
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+		})
+	}
+}
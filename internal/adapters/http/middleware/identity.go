@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
+)
+
+const (
+	DefaultIdentityHeader = "X-User-Id"
+	DefaultRolesHeader    = "X-User-Roles"
+)
+
+// Identity stores the caller named by header in the request context. The API
+// sits behind a gateway that authenticates users and forwards their id, so
+// the header is trusted as is. The comma separated roles in rolesHeader grant
+// privileges any client could claim, so they are only read when trustRoles
+// says the gateway sets that header and drops the one clients send.
+func Identity(header, rolesHeader string, trustRoles bool) gin.HandlerFunc {
+	if header == "" {
+		header = DefaultIdentityHeader
+	}
+
+	if rolesHeader == "" {
+		rolesHeader = DefaultRolesHeader
+	}
+
+	return func(c *gin.Context) {
+		if id := strings.TrimSpace(c.GetHeader(header)); id != "" {
+			caller := identity.Caller{Id: id}
+			if trustRoles {
+				caller.Roles = parseRoles(c.GetHeader(rolesHeader))
+			}
+
+			ctx := identity.NewContext(c.Request.Context(), caller)
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		c.Next()
+	}
+}
+
+func parseRoles(header string) []string {
+	var roles []string
+	for _, role := range strings.Split(header, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/adapters/http/middleware"
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentity(t *testing.T) {
+	testCases := []struct {
+		name       string
+		header     string
+		trustRoles bool
+		headers    map[string]string
+		expected   identity.Caller
+	}{
+		{
+			name:     "When default header is set, should expose the caller to the handler",
+			headers:  map[string]string{"X-User-Id": "jane"},
+			expected: identity.Caller{Id: "jane"},
+		},
+		{
+			name:     "When a custom header is configured, should read the caller from it",
+			header:   "X-Forwarded-User",
+			headers:  map[string]string{"X-Forwarded-User": "john", "X-User-Id": "jane"},
+			expected: identity.Caller{Id: "john"},
+		},
+		{
+			name:       "When roles header is trusted and set, should expose the trimmed roles",
+			trustRoles: true,
+			headers:    map[string]string{"X-User-Id": "jane", "X-User-Roles": "viewer, admin,,"},
+			expected:   identity.Caller{Id: "jane", Roles: []string{"viewer", "admin"}},
+		},
+		{
+			name:     "When roles header is not trusted, should ignore the roles",
+			headers:  map[string]string{"X-User-Id": "jane", "X-User-Roles": "admin"},
+			expected: identity.Caller{Id: "jane"},
+		},
+		{
+			name:       "When only roles header is set, should leave the caller anonymous",
+			trustRoles: true,
+			headers:    map[string]string{"X-User-Roles": "admin"},
+			expected:   identity.Caller{},
+		},
+		{
+			name:     "When header is blank, should leave the caller anonymous",
+			headers:  map[string]string{"X-User-Id": "  "},
+			expected: identity.Caller{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(middleware.Identity(tc.header, "", tc.trustRoles))
+
+			var actual identity.Caller
+			router.GET("/", func(c *gin.Context) {
+				actual, _ = identity.FromContext(c.Request.Context())
+			})
+
+			req, _ := http.NewRequest("GET", "/", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/http/middleware"
+)
+
+func TestAccessLog(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		status     int
+		assertions func(t *testing.T, code int, records []map[string]any)
+	}{
+		{
+			name:   "When request succeeds, should log it at info level with its route and request id",
+			path:   "/realstate/1",
+			status: http.StatusOK,
+			assertions: func(t *testing.T, code int, records []map[string]any) {
+				record := records[len(records)-1]
+				assert.Equal(t, "INFO", record["level"])
+				assert.Equal(t, "request served", record["msg"])
+				assert.Equal(t, "GET", record["method"])
+				assert.Equal(t, "/realstate/:id", record["route"])
+				assert.Equal(t, "/realstate/1", record["path"])
+				assert.Equal(t, float64(http.StatusOK), record["status"])
+				assert.Equal(t, "abc", record["request_id"])
+			},
+		},
+		{
+			name:   "When handler fails, should log it at error level",
+			path:   "/realstate/1",
+			status: http.StatusInternalServerError,
+			assertions: func(t *testing.T, code int, records []map[string]any) {
+				record := records[len(records)-1]
+				assert.Equal(t, "ERROR", record["level"])
+				assert.Equal(t, float64(http.StatusInternalServerError), record["status"])
+			},
+		},
+		{
+			name: "When handler panics, should answer 500 and log the panic before the request",
+			path: "/panic",
+			assertions: func(t *testing.T, code int, records []map[string]any) {
+				assert.Equal(t, http.StatusInternalServerError, code)
+				assert.Len(t, records, 2)
+				assert.Equal(t, "request panicked", records[0]["msg"])
+				assert.Equal(t, "boom", records[0]["panic"])
+				assert.Equal(t, "abc", records[0]["request_id"])
+				assert.Contains(t, records[0]["stack"], "runtime/debug.Stack")
+				assert.Equal(t, "request served", records[1]["msg"])
+				assert.Equal(t, "ERROR", records[1]["level"])
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			var out bytes.Buffer
+			router := gin.New()
+			router.Use(
+				middleware.RequestID("", slog.New(slog.NewJSONHandler(&out, nil))),
+				middleware.AccessLog(),
+				middleware.Recovery(),
+			)
+			router.GET("/realstate/:id", func(c *gin.Context) { c.Status(tc.status) })
+			router.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			req.Header.Set("X-Request-ID", "abc")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var records []map[string]any
+			for decoder := json.NewDecoder(&out); decoder.More(); {
+				var record map[string]any
+				assert.NoError(t, decoder.Decode(&record))
+				records = append(records, record)
+			}
+
+			tc.assertions(t, w.Code, records)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UnmatchedRoute labels requests that hit no route, so unknown paths cannot
+// grow the number of series.
+const UnmatchedRoute = "unmatched"
+
+// Metrics counts requests and observes their latency in registerer, labelled
+// by method, route template and status. It must be used before the routes it
+// measures are built.
+func Metrics(registerer prometheus.Registerer) gin.HandlerFunc {
+	labels := []string{"method", "route", "status"}
+
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by method, route template and status.",
+	}, labels)
+
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests, by method, route template and status.",
+		Buckets: prometheus.DefBuckets,
+	}, labels)
+
+	registerer.MustRegister(requests, duration)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = UnmatchedRoute
+		}
+
+		values := []string{c.Request.Method, route, strconv.Itoa(c.Writer.Status())}
+		requests.WithLabelValues(values...).Inc()
+		duration.WithLabelValues(values...).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/http/middleware"
+)
+
+func TestMetrics(t *testing.T) {
+	testCases := []struct {
+		name     string
+		paths    []string
+		expected map[[3]string]float64
+	}{
+		{
+			name:  "When routes have parameters, should label requests by route template",
+			paths: []string{"/realstates/1", "/realstates/2", "/realstates/3"},
+			expected: map[[3]string]float64{
+				{"GET", "/realstates/:id", "200"}: 3,
+			},
+		},
+		{
+			name:  "When handler fails, should label requests by the status written",
+			paths: []string{"/fail", "/realstates/1"},
+			expected: map[[3]string]float64{
+				{"GET", "/fail", "500"}:           1,
+				{"GET", "/realstates/:id", "200"}: 1,
+			},
+		},
+		{
+			name:  "When no route matches, should label requests as unmatched",
+			paths: []string{"/unknown/1", "/unknown/2"},
+			expected: map[[3]string]float64{
+				{"GET", middleware.UnmatchedRoute, "404"}: 2,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			registry := prometheus.NewRegistry()
+
+			router := gin.New()
+			router.Use(middleware.Metrics(registry))
+			router.GET("/realstates/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+			router.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+			for _, path := range tc.paths {
+				req, _ := http.NewRequest("GET", path, nil)
+				router.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			families, err := registry.Gather()
+			assert.NoError(t, err)
+
+			actual := map[[3]string]float64{}
+			for _, family := range families {
+				if family.GetName() != "http_requests_total" {
+					continue
+				}
+
+				for _, metric := range family.GetMetric() {
+					var key [3]string
+					for _, label := range metric.GetLabel() {
+						switch label.GetName() {
+						case "method":
+							key[0] = label.GetValue()
+						case "route":
+							key[1] = label.GetValue()
+						case "status":
+							key[2] = label.GetValue()
+						}
+					}
+					actual[key] = metric.GetCounter().GetValue()
+				}
+			}
+
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, len(tc.expected), testutil.CollectAndCount(registry, "http_request_duration_seconds"))
+		})
+	}
+}
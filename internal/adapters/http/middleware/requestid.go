@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/natanchagas/gin-crud/internal/pkg/logging"
+)
+
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the ids taken from clients, which end up in every
+// log record of their request.
+const maxRequestIDLength = 128
+
+// RequestID tags the request with the id sent in header, or a new one when
+// it is missing or unfit for logs, and echoes it in the response. Handlers
+// and repositories log through the request context with logger scoped to the
+// id, and to the trace id when the request is traced.
+func RequestID(header string, logger *slog.Logger) gin.HandlerFunc {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	return func(c *gin.Context) {
+		id := c.GetHeader(header)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Header(header, id)
+
+		scoped := logger.With("request_id", id)
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			scoped = scoped.With("trace_id", span.TraceID().String())
+		}
+
+		ctx := logging.NewContext(c.Request.Context(), scoped)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// validRequestID accepts printable ASCII without spaces, so a client cannot
+// forge log records through its id.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
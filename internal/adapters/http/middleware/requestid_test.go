@@ -0,0 +1,95 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/http/middleware"
+	"github.com/natanchagas/gin-crud/internal/pkg/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		header     string
+		headers    map[string]string
+		assertions func(t *testing.T, id string)
+	}{
+		{
+			name:    "When request carries an id, should keep it",
+			headers: map[string]string{"X-Request-ID": "abc-123"},
+			assertions: func(t *testing.T, id string) {
+				assert.Equal(t, "abc-123", id)
+			},
+		},
+		{
+			name:    "When a custom header is configured, should read the id from it",
+			header:  "X-Correlation-ID",
+			headers: map[string]string{"X-Correlation-ID": "abc-123", "X-Request-ID": "def-456"},
+			assertions: func(t *testing.T, id string) {
+				assert.Equal(t, "abc-123", id)
+			},
+		},
+		{
+			name: "When request carries no id, should generate one",
+			assertions: func(t *testing.T, id string) {
+				assert.NoError(t, uuid.Validate(id))
+			},
+		},
+		{
+			name:    "When id holds spaces, should replace it",
+			headers: map[string]string{"X-Request-ID": "abc\" level=ERROR"},
+			assertions: func(t *testing.T, id string) {
+				assert.NoError(t, uuid.Validate(id))
+			},
+		},
+		{
+			name:    "When id is too long, should replace it",
+			headers: map[string]string{"X-Request-ID": strings.Repeat("a", 129)},
+			assertions: func(t *testing.T, id string) {
+				assert.NoError(t, uuid.Validate(id))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			var out bytes.Buffer
+			router := gin.New()
+			router.Use(middleware.RequestID(tc.header, slog.New(slog.NewJSONHandler(&out, nil))))
+			router.GET("/", func(c *gin.Context) {
+				logging.FromContext(c.Request.Context()).Info("handled")
+			})
+
+			req, _ := http.NewRequest("GET", "/", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			header := tc.header
+			if header == "" {
+				header = middleware.DefaultRequestIDHeader
+			}
+			id := w.Header().Get(header)
+
+			var record map[string]any
+			assert.NoError(t, json.Unmarshal(out.Bytes(), &record))
+			assert.Equal(t, id, record["request_id"], "handlers must log with the request id")
+
+			tc.assertions(t, id)
+		})
+	}
+}
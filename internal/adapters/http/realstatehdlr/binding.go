@@ -0,0 +1,36 @@
+package realstatehdlr
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+func bindRealState(c *gin.Context, realState *domain.RealState) error {
+	decoded, err := domain.DecodeRealState(c.Request.Body)
+	if err != nil {
+		if errors.Is(err, domain.ErrMalformedRealState) {
+			return customerrors.BadRequest
+		}
+
+		return err
+	}
+
+	*realState = decoded
+	return nil
+}
+
+func toValidationError(verr domain.ValidationError) customerrors.Error {
+	details := make([]customerrors.Detail, len(verr.Fields))
+	for i, f := range verr.Fields {
+		details[i] = customerrors.Detail{
+			Field:   f.Field,
+			Rule:    f.Rule,
+			Message: f.Message,
+		}
+	}
+
+	return customerrors.Validation.WithDetails(details...)
+}
@@ -0,0 +1,140 @@
+package realstatehdlr
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/cursor"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+type cursorToken struct {
+	Field string `json:"f"`
+	Desc  bool   `json:"d,omitempty"`
+	Id    uint64 `json:"i"`
+	Value string `json:"v,omitempty"`
+}
+
+type keysetResponse struct {
+	Data       []domain.RealState `json:"data"`
+	Limit      uint64             `json:"limit"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	Links      pageLinks          `json:"links"`
+}
+
+func (h *RealStateHandler) listAfter(c *gin.Context, token string) {
+	ctx := c.Request.Context()
+
+	query, err := h.parseKeysetQuery(c, token)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	realStates, err := h.RealStateService.ListAfter(ctx, query)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	res, err := h.newKeysetResponse(c.Request.URL, realStates)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	c.JSON(200, res)
+	return
+}
+
+func (h *RealStateHandler) parseKeysetQuery(c *gin.Context, token string) (ports.KeysetQuery, error) {
+	if _, ok := c.GetQuery("offset"); ok {
+		return ports.KeysetQuery{}, customerrors.BadRequest
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		return ports.KeysetQuery{}, err
+	}
+
+	sort, err := parseSort(c.Query("sort"))
+	if err != nil || len(sort) > 1 {
+		return ports.KeysetQuery{}, customerrors.BadRequest
+	}
+
+	query := ports.KeysetQuery{
+		Sort:  ports.Sort{Field: ports.SortById},
+		Limit: page.Limit,
+	}
+
+	if len(sort) == 1 {
+		query.Sort = sort[0]
+	}
+
+	if token == "" {
+		return query, nil
+	}
+
+	var t cursorToken
+	if err := cursor.Decode(h.CursorSecret, token, &t); err != nil {
+		return ports.KeysetQuery{}, customerrors.BadRequest
+	}
+
+	after := ports.Sort{Field: ports.SortField(t.Field), Desc: t.Desc}
+
+	// A cursor is only meaningful for the ordering it was issued for.
+	if len(sort) == 1 && sort[0] != after {
+		return ports.KeysetQuery{}, customerrors.BadRequest
+	}
+
+	query.Sort = after
+	query.After = &ports.Cursor{
+		Sort:      after,
+		LastId:    t.Id,
+		LastValue: t.Value,
+	}
+
+	return query, nil
+}
+
+func (h *RealStateHandler) newKeysetResponse(u *url.URL, page ports.RealStateKeysetPage) (keysetResponse, error) {
+	res := keysetResponse{
+		Data:  page.RealStates,
+		Limit: page.Limit,
+	}
+
+	if res.Data == nil {
+		res.Data = []domain.RealState{}
+	}
+
+	if page.Next == nil {
+		return res, nil
+	}
+
+	token, err := cursor.Encode(h.CursorSecret, cursorToken{
+		Field: string(page.Next.Sort.Field),
+		Desc:  page.Next.Sort.Desc,
+		Id:    page.Next.LastId,
+		Value: page.Next.LastValue,
+	})
+	if err != nil {
+		return keysetResponse{}, err
+	}
+
+	q := u.Query()
+	q.Set("cursor", token)
+	q.Set("limit", strconv.FormatUint(page.Limit, 10))
+
+	link := url.URL{
+		Path:     u.Path,
+		RawQuery: q.Encode(),
+	}
+
+	res.NextCursor = token
+	res.Links.Next = link.String()
+
+	return res, nil
+}
@@ -0,0 +1,39 @@
+package realstatehdlr
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/natanchagas/gin-crud/internal/pkg/logging"
+)
+
+// renderError is the single place errors leave the handler. Problem details
+// are the default; clients asking for application/json ahead of it keep
+// receiving the legacy customerrors.Error body.
+func renderError(c *gin.Context, err error) {
+	var cerr customerrors.Error
+
+	var verr domain.ValidationError
+	if errors.As(err, &verr) {
+		cerr = toValidationError(verr)
+	} else if e, ok := err.(customerrors.Error); ok {
+		cerr = e
+	} else {
+		// Anything else is a bug or a failure nobody translated, so keep
+		// the cause, which the response hides.
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).ErrorContext(ctx, "unexpected error", "error", err)
+
+		cerr = customerrors.Unexpected
+	}
+
+	if c.NegotiateFormat(customerrors.ProblemContentType, gin.MIMEJSON) == gin.MIMEJSON {
+		c.JSON(cerr.StatusCode, cerr)
+		return
+	}
+
+	c.Header("Content-Type", customerrors.ProblemContentType)
+	c.JSON(cerr.StatusCode, cerr.Problem(c.Request.URL.Path))
+}
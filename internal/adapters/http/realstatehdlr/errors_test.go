@@ -0,0 +1,179 @@
+package realstatehdlr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/adapters/http/middleware"
+	"github.com/natanchagas/gin-crud/internal/adapters/http/realstatehdlr"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/mocks"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestErrorNegotiation(t *testing.T) {
+	type input struct {
+		method string
+		path   string
+		body   string
+		accept string
+	}
+
+	type output struct {
+		httpCode    int
+		contentType string
+		body        string
+	}
+
+	testCases := []struct {
+		name    string
+		input   input
+		mocking func(m *mocks.RealStateService) output
+	}{
+		{
+			name:  "When accept is not given, should return problem details",
+			input: input{method: "GET", path: "/realstate/1"},
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Get", mock.Anything, uint64(1)).
+					Return(domain.RealState{}, customerrors.NotFound)
+
+				b, err := json.Marshal(customerrors.NotFound.Problem("/realstate/1"))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode:    http.StatusNotFound,
+					contentType: "application/problem+json",
+					body:        string(b),
+				}
+			},
+		},
+		{
+			name:  "When accept prefers problem details, should return problem details",
+			input: input{method: "GET", path: "/realstate/abc", accept: "application/problem+json, application/json"},
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest.Problem("/realstate/abc"))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode:    http.StatusBadRequest,
+					contentType: "application/problem+json",
+					body:        string(b),
+				}
+			},
+		},
+		{
+			name:  "When accept prefers plain json, should return the legacy error",
+			input: input{method: "GET", path: "/realstate/1", accept: "application/json"},
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Get", mock.Anything, uint64(1)).
+					Return(domain.RealState{}, customerrors.NotFound)
+
+				b, err := json.Marshal(customerrors.NotFound)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode:    http.StatusNotFound,
+					contentType: "application/json; charset=utf-8",
+					body:        string(b),
+				}
+			},
+		},
+		{
+			name:  "When payload fails validation, should return problem details with the invalid fields",
+			input: input{method: "POST", path: "/realstate/", body: `{"registration":987654321,"address":"456 Elm St","size":"big","price":250000.5,"state":"SP"}`},
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.Validation.WithDetails(
+					customerrors.Detail{Field: "size", Rule: "type", Message: "must be a non-negative integer"},
+				).Problem("/realstate/"))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode:    http.StatusBadRequest,
+					contentType: "application/problem+json",
+					body:        string(b),
+				}
+			},
+		},
+		{
+			name:  "When service fails with an unknown error, should return an unexpected problem",
+			input: input{method: "DELETE", path: "/realstate/1", accept: "*/*"},
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Delete", mock.Anything, uint64(1), uint64(0)).
+					Return(assert.AnError)
+
+				b, err := json.Marshal(customerrors.Unexpected.Problem("/realstate/1"))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode:    http.StatusInternalServerError,
+					contentType: "application/problem+json",
+					body:        string(b),
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+
+			expected := tc.mocking(s)
+
+			hdlr := realstatehdlr.NewRealStateHandler(s)
+			hdlr.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.input.method, tc.input.path, bytes.NewBufferString(tc.input.body))
+			if tc.input.accept != "" {
+				req.Header.Set("Accept", tc.input.accept)
+			}
+			router.ServeHTTP(w, req)
+
+			actual := output{
+				httpCode:    w.Code,
+				contentType: w.Header().Get("Content-Type"),
+				body:        w.Body.String(),
+			}
+
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestUnexpectedErrorIsLogged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var out bytes.Buffer
+	router := gin.New()
+	router.Use(middleware.RequestID("", slog.New(slog.NewJSONHandler(&out, nil))))
+
+	s := mocks.NewRealStateService(t)
+	s.
+		On("Delete", mock.Anything, uint64(1), uint64(0)).
+		Return(assert.AnError)
+
+	realstatehdlr.NewRealStateHandler(s).BuildRoutes(router)
+
+	req, _ := http.NewRequest("DELETE", "/realstate/1", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "abc", record["request_id"])
+	assert.Equal(t, assert.AnError.Error(), record["error"])
+}
@@ -0,0 +1,48 @@
+package realstatehdlr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+func etag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// ifMatch returns the version the client expects to change, or zero when the
+// write should not be conditional. Only a single strong ETag or "*" can be
+// honoured by the compare-and-swap, anything else cannot match.
+func (h *RealStateHandler) ifMatch(c *gin.Context) (uint64, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" {
+		if h.RequireIfMatch {
+			return 0, customerrors.PreconditionRequired
+		}
+
+		return 0, nil
+	}
+
+	if value == "*" {
+		return 0, nil
+	}
+
+	unquoted, ok := strings.CutPrefix(value, `"`)
+	if !ok {
+		return 0, customerrors.PreconditionFailed
+	}
+
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, customerrors.PreconditionFailed
+	}
+
+	version, err := strconv.ParseUint(unquoted, 10, 64)
+	if err != nil || version == 0 {
+		return 0, customerrors.PreconditionFailed
+	}
+
+	return version, nil
+}
@@ -0,0 +1,177 @@
+package realstatehdlr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/adapters/http/realstatehdlr"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/mocks"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	type input struct {
+		method  string
+		path    string
+		body    string
+		ifMatch string
+		strict  bool
+	}
+
+	type output struct {
+		httpCode int
+		etag     string
+		body     string
+	}
+
+	stored := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(27500000),
+		State:        "SP",
+		Version:      3,
+	}
+
+	body := `{"registration":987654321,"address":"456 Elm St","size":200,"price":275000.00,"state":"SP"}`
+
+	testCases := []struct {
+		name    string
+		input   input
+		mocking func(m *mocks.RealStateService) output
+	}{
+		{
+			name:  "When real state is read, should return its version as ETag",
+			input: input{method: "GET", path: "/realstate/1"},
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Get", mock.Anything, uint64(1)).
+					Return(stored, nil)
+
+				b, err := json.Marshal(stored)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusOK, etag: `"3"`, body: string(b)}
+			},
+		},
+		{
+			name:  "When If-Match holds the current version, should update and return the new ETag",
+			input: input{method: "PUT", path: "/realstate/1", body: body, ifMatch: `"3"`, strict: true},
+			mocking: func(m *mocks.RealStateService) output {
+				in := stored
+				in.Id = 0
+
+				out := stored
+				out.Version = 4
+
+				m.
+					On("Update", mock.Anything, in, uint64(1)).
+					Return(out, nil)
+
+				b, err := json.Marshal(out)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusOK, etag: `"4"`, body: string(b)}
+			},
+		},
+		{
+			name:  "When If-Match is stale, should return 412",
+			input: input{method: "PUT", path: "/realstate/1", body: body, ifMatch: `"2"`},
+			mocking: func(m *mocks.RealStateService) output {
+				in := stored
+				in.Id = 0
+				in.Version = 2
+
+				m.
+					On("Update", mock.Anything, in, uint64(1)).
+					Return(domain.RealState{}, customerrors.PreconditionFailed)
+
+				b, err := json.Marshal(customerrors.PreconditionFailed)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusPreconditionFailed, body: string(b)}
+			},
+		},
+		{
+			name:  "When If-Match is missing in strict mode, should return 428 without calling the service",
+			input: input{method: "PUT", path: "/realstate/1", body: body, strict: true},
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.PreconditionRequired)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusPreconditionRequired, body: string(b)}
+			},
+		},
+		{
+			name:  "When If-Match is a weak ETag, should return 412 without calling the service",
+			input: input{method: "DELETE", path: "/realstate/1", ifMatch: `W/"3"`},
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.PreconditionFailed)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusPreconditionFailed, body: string(b)}
+			},
+		},
+		{
+			name:  "When If-Match is a wildcard in strict mode, should delete unconditionally",
+			input: input{method: "DELETE", path: "/realstate/1", ifMatch: "*", strict: true},
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Delete", mock.Anything, uint64(1), uint64(0)).
+					Return(nil)
+
+				return output{httpCode: http.StatusNoContent}
+			},
+		},
+		{
+			name:  "When If-Match holds the current version, should delete that version",
+			input: input{method: "DELETE", path: "/realstate/1", ifMatch: `"3"`, strict: true},
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Delete", mock.Anything, uint64(1), uint64(3)).
+					Return(nil)
+
+				return output{httpCode: http.StatusNoContent}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+
+			expected := tc.mocking(s)
+
+			hdlr := realstatehdlr.NewRealStateHandler(s)
+			hdlr.RequireIfMatch = tc.input.strict
+			hdlr.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.input.method, tc.input.path, bytes.NewBufferString(tc.input.body))
+			req.Header.Set("Accept", "application/json")
+			if tc.input.ifMatch != "" {
+				req.Header.Set("If-Match", tc.input.ifMatch)
+			}
+			router.ServeHTTP(w, req)
+
+			actual := output{
+				httpCode: w.Code,
+				etag:     w.Header().Get("ETag"),
+				body:     w.Body.String(),
+			}
+
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package realstatehdlr
 
 import (
+	"io"
+	"math"
+	"net/url"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -9,8 +12,35 @@ import (
 	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
 )
 
+type listResponse struct {
+	Data   []domain.RealState `json:"data"`
+	Total  uint64             `json:"total"`
+	Limit  uint64             `json:"limit"`
+	Offset uint64             `json:"offset"`
+	Links  pageLinks          `json:"links"`
+}
+
+type historyResponse struct {
+	Data   []domain.AuditEntry `json:"data"`
+	Total  uint64              `json:"total"`
+	Limit  uint64              `json:"limit"`
+	Offset uint64              `json:"offset"`
+	Links  pageLinks           `json:"links"`
+}
+
+type pageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type purgeResponse struct {
+	Purged uint64 `json:"purged"`
+}
+
 type RealStateHandler struct {
 	RealStateService ports.RealStateService
+	CursorSecret     []byte
+	RequireIfMatch   bool
 }
 
 func NewRealStateHandler(service ports.RealStateService) *RealStateHandler {
@@ -24,23 +54,19 @@ func (h *RealStateHandler) create(c *gin.Context) {
 
 	var realState domain.RealState
 
-	err := c.BindJSON(&realState)
+	err := bindRealState(c, &realState)
 	if err != nil {
-		c.JSON(400, customerrors.BadRequest)
+		renderError(c, err)
 		return
 	}
 
 	realState, err = h.RealStateService.Create(ctx, realState)
 	if err != nil {
-		if cerr, ok := err.(customerrors.Error); ok {
-			c.JSON(cerr.StatusCode, cerr)
-			return
-		}
-
-		c.JSON(500, customerrors.Unexpected)
+		renderError(c, err)
 		return
 	}
 
+	c.Header("ETag", etag(realState.Version))
 	c.JSON(201, realState)
 	return
 }
@@ -51,77 +77,164 @@ func (h *RealStateHandler) get(c *gin.Context) {
 
 	rid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.JSON(400, customerrors.BadRequest)
+		renderError(c, customerrors.BadRequest)
 		return
 	}
 
 	realstate, err := h.RealStateService.Get(ctx, rid)
 	if err != nil {
-		if cerr, ok := err.(customerrors.Error); ok {
-			c.JSON(cerr.StatusCode, cerr)
-			return
-		}
-
-		c.JSON(500, customerrors.Unexpected)
+		renderError(c, err)
 		return
 	}
 
+	c.Header("ETag", etag(realstate.Version))
 	c.JSON(200, realstate)
 	return
 }
 
+func (h *RealStateHandler) list(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if token, ok := c.GetQuery("cursor"); ok {
+		h.listAfter(c, token)
+		return
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	realStates, err := h.RealStateService.List(ctx, page)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	c.JSON(200, newListResponse(c.Request.URL, realStates))
+	return
+}
+
+func (h *RealStateHandler) search(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, err := parseQuery(c)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	realStates, err := h.RealStateService.Search(ctx, query)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	c.JSON(200, newListResponse(c.Request.URL, realStates))
+	return
+}
+
 func (h *RealStateHandler) update(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
 
 	rid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.JSON(400, customerrors.BadRequest)
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	version, err := h.ifMatch(c)
+	if err != nil {
+		renderError(c, err)
 		return
 	}
 
 	var realState domain.RealState
 
-	err = c.BindJSON(&realState)
+	err = bindRealState(c, &realState)
 	if err != nil {
-		c.JSON(400, customerrors.BadRequest)
+		renderError(c, err)
 		return
 	}
+	realState.Version = version
 
 	realState, err = h.RealStateService.Update(ctx, realState, rid)
 	if err != nil {
-		if cerr, ok := err.(customerrors.Error); ok {
-			c.JSON(cerr.StatusCode, cerr)
-			return
-		}
-
-		c.JSON(500, customerrors.Unexpected)
+		renderError(c, err)
 		return
 	}
 
+	c.Header("ETag", etag(realState.Version))
 	c.JSON(200, realState)
 	return
 
 }
 
+func (h *RealStateHandler) patch(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	rid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	format := ports.PatchFormat(c.ContentType())
+	if format != ports.MergePatch && format != ports.JSONPatch {
+		c.Header("Accept-Patch", string(ports.MergePatch)+", "+string(ports.JSONPatch))
+		renderError(c, customerrors.UnsupportedMediaType)
+		return
+	}
+
+	version, err := h.ifMatch(c)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	document, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	realState, err := h.RealStateService.Patch(ctx, ports.RealStatePatch{
+		Format:   format,
+		Document: document,
+		Version:  version,
+	}, rid)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	c.Header("ETag", etag(realState.Version))
+	c.JSON(200, realState)
+	return
+}
+
 func (h *RealStateHandler) delete(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
 
 	rid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.JSON(400, customerrors.BadRequest)
+		renderError(c, customerrors.BadRequest)
 		return
 	}
 
-	err = h.RealStateService.Delete(ctx, rid)
+	version, err := h.ifMatch(c)
 	if err != nil {
-		if cerr, ok := err.(customerrors.Error); ok {
-			c.JSON(cerr.StatusCode, cerr)
-			return
-		}
+		renderError(c, err)
+		return
+	}
 
-		c.JSON(500, customerrors.Unexpected)
+	err = h.RealStateService.Delete(ctx, rid, version)
+	if err != nil {
+		renderError(c, err)
 		return
 	}
 
@@ -129,11 +242,191 @@ func (h *RealStateHandler) delete(c *gin.Context) {
 	return
 }
 
+func (h *RealStateHandler) restore(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	rid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	realState, err := h.RealStateService.Restore(ctx, rid)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	c.Header("ETag", etag(realState.Version))
+	c.JSON(200, realState)
+	return
+}
+
+func (h *RealStateHandler) history(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	rid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	history, err := h.RealStateService.History(ctx, rid, page)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	c.JSON(200, newHistoryResponse(c.Request.URL, history))
+	return
+}
+
+func (h *RealStateHandler) prices(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	rid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		renderError(c, customerrors.BadRequest)
+		return
+	}
+
+	prices, err := h.RealStateService.Prices(ctx, rid)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	c.JSON(200, prices)
+	return
+}
+
+func (h *RealStateHandler) purge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	purged, err := h.RealStateService.Purge(ctx)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+
+	c.JSON(200, purgeResponse{Purged: purged})
+	return
+}
+
 func (h *RealStateHandler) BuildRoutes(router *gin.Engine) {
 	realState := router.Group("/realstate/")
 
 	realState.POST("/", h.create)
+	realState.GET("/", h.list)
+	realState.GET("/search", h.search)
 	realState.GET("/:id", h.get)
 	realState.PUT("/:id", h.update)
+	realState.PATCH("/:id", h.patch)
 	realState.DELETE("/:id", h.delete)
+	realState.POST("/:id/restore", h.restore)
+	realState.GET("/:id/history", h.history)
+	realState.GET("/:id/prices", h.prices)
+	realState.POST("/purge", h.purge)
+}
+
+func parsePage(c *gin.Context) (ports.Page, error) {
+	page := ports.Page{
+		Limit:  ports.DefaultPageLimit,
+		Offset: 0,
+	}
+
+	if limit, ok := c.GetQuery("limit"); ok {
+		l, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil || l == 0 || l > ports.MaxPageLimit {
+			return ports.Page{}, customerrors.BadRequest
+		}
+
+		page.Limit = l
+	}
+
+	if offset, ok := c.GetQuery("offset"); ok {
+		o, err := strconv.ParseUint(offset, 10, 64)
+		if err != nil || o > math.MaxInt64 {
+			return ports.Page{}, customerrors.BadRequest
+		}
+
+		page.Offset = o
+	}
+
+	return page, nil
+}
+
+func newListResponse(u *url.URL, page ports.RealStatePage) listResponse {
+	res := listResponse{
+		Data:   page.RealStates,
+		Total:  page.Total,
+		Limit:  page.Page.Limit,
+		Offset: page.Page.Offset,
+	}
+
+	if res.Data == nil {
+		res.Data = []domain.RealState{}
+	}
+
+	res.Links = newPageLinks(u, page.Page, page.Total)
+
+	return res
+}
+
+func newHistoryResponse(u *url.URL, page ports.AuditPage) historyResponse {
+	res := historyResponse{
+		Data:   page.Entries,
+		Total:  page.Total,
+		Limit:  page.Page.Limit,
+		Offset: page.Page.Offset,
+		Links:  newPageLinks(u, page.Page, page.Total),
+	}
+
+	if res.Data == nil {
+		res.Data = []domain.AuditEntry{}
+	}
+
+	return res
+}
+
+func newPageLinks(u *url.URL, page ports.Page, total uint64) pageLinks {
+	var links pageLinks
+
+	// Compared by subtraction so the offset of the next page cannot wrap.
+	if page.Limit < total && page.Offset < total-page.Limit {
+		links.Next = pageURL(u, page.Limit, page.Offset+page.Limit)
+	}
+
+	if page.Offset > 0 {
+		prev := uint64(0)
+		if page.Offset > page.Limit {
+			prev = page.Offset - page.Limit
+		}
+
+		links.Prev = pageURL(u, page.Limit, prev)
+	}
+
+	return links
+}
+
+func pageURL(u *url.URL, limit, offset uint64) string {
+	q := u.Query()
+	q.Set("limit", strconv.FormatUint(limit, 10))
+	q.Set("offset", strconv.FormatUint(offset, 10))
+
+	link := url.URL{
+		Path:     u.Path,
+		RawQuery: q.Encode(),
+	}
+
+	return link.String()
 }
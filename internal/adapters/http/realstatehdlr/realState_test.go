@@ -7,13 +7,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/natanchagas/gin-crud/internal/adapters/http/realstatehdlr"
 	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
 	"github.com/natanchagas/gin-crud/internal/mocks"
+	"github.com/natanchagas/gin-crud/internal/pkg/cursor"
 	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -92,6 +96,70 @@ func TestCreate(t *testing.T) {
 				assert.Equal(t, expected, actual)
 			},
 		},
+		{
+			name:  "When payload fails domain validation, should return 400 listing every invalid field",
+			input: `{"registration":987654321,"address":"456 Elm St","size":200,"price":0,"state":"XX"}`,
+			mocking: func(m *mocks.RealStateService, realState string) output {
+				var rs domain.RealState
+
+				err := json.Unmarshal([]byte(realState), &rs)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				verr := domain.ValidationError{
+					Fields: []domain.FieldError{
+						{Field: "price", Rule: domain.RulePositive, Message: "must be greater than zero"},
+						{Field: "state", Rule: domain.RuleState, Message: "must be a brazilian state code"},
+					},
+				}
+
+				m.
+					On("Create", mock.AnythingOfType("context.backgroundCtx"), rs).
+					Return(domain.RealState{}, verr)
+
+				b, err := json.Marshal(customerrors.Validation.WithDetails(
+					customerrors.Detail{Field: "price", Rule: domain.RulePositive, Message: "must be greater than zero"},
+					customerrors.Detail{Field: "state", Rule: domain.RuleState, Message: "must be a brazilian state code"},
+				))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When registration already exists, should return 409",
+			input: `{"registration":987654321,"address":"456 Elm St","size":200,"price":250000.5,"state":"CA"}`,
+			mocking: func(m *mocks.RealStateService, realState string) output {
+				var rs domain.RealState
+
+				err := json.Unmarshal([]byte(realState), &rs)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				m.
+					On("Create", mock.AnythingOfType("context.backgroundCtx"), rs).
+					Return(domain.RealState{}, customerrors.Conflict)
+
+				b, err := json.Marshal(customerrors.Conflict)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusConflict,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
 		{
 			name:  "When payload is valid, but service fails with an unexpected error, should return error",
 			input: `{"registration":987654321,"address":"456 Elm St","size":200,"price":250000.5,"state":"CA"}`,
@@ -119,6 +187,60 @@ func TestCreate(t *testing.T) {
 				assert.Equal(t, expected, actual)
 			},
 		},
+		{
+			name:  "When a field has the wrong type, should return 400 pointing at the field",
+			input: `{"registration":"987654321","address":"456 Elm St","size":200,"price":250000.5,"state":"SP"}`,
+			mocking: func(m *mocks.RealStateService, realState string) output {
+				b, err := json.Marshal(customerrors.Validation.WithDetails(
+					customerrors.Detail{Field: "registration", Rule: "type", Message: "must be a non-negative integer"},
+				))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When price has more than two decimal places, should return 400 pointing at the price",
+			input: `{"registration":987654321,"address":"456 Elm St","size":200,"price":250000.505,"state":"SP"}`,
+			mocking: func(m *mocks.RealStateService, realState string) output {
+				b, err := json.Marshal(customerrors.Validation.WithDetails(
+					customerrors.Detail{Field: "price", Rule: "type", Message: "must be a decimal number with at most two decimal places"},
+				))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When payload has an unknown field, should return 400 pointing at the field",
+			input: `{"registration":987654321,"address":"456 Elm St","size":200,"price":250000.5,"state":"SP","rooms":3}`,
+			mocking: func(m *mocks.RealStateService, realState string) output {
+				b, err := json.Marshal(customerrors.Validation.WithDetails(
+					customerrors.Detail{Field: "rooms", Rule: "unknown_field", Message: "is not a known field"},
+				))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
 		{
 			name:  "When payload is invalid, should return error",
 			input: `{"invalid"}`,
@@ -152,6 +274,7 @@ func TestCreate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("POST", "/realstate/", bytes.NewBuffer([]byte(tc.input)))
+			req.Header.Set("Accept", "application/json")
 			router.ServeHTTP(w, req)
 
 			var actual output
@@ -187,7 +310,7 @@ func TestGet(t *testing.T) {
 					Registration: 987654321,
 					Address:      "456 Elm St",
 					Size:         200,
-					Price:        250000.50,
+					Price:        domain.NewMoney(25000050),
 					State:        "CA",
 				}
 
@@ -311,6 +434,7 @@ func TestGet(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("GET", fmt.Sprintf("/realstate/%s", tc.input), nil)
+			req.Header.Set("Accept", "application/json")
 			router.ServeHTTP(w, req)
 
 			var actual output
@@ -379,6 +503,46 @@ func TestUpdate(t *testing.T) {
 				assert.Equal(t, expected, actual)
 			},
 		},
+		{
+			name: "When body carries metadata, should ignore it and return the stored values",
+			input: input{
+				body: `{"registration": 987654321,"address": "456 Elm St","size": 200,"price": 275000.00,"state": "SP","created_at":"2000-01-01T00:00:00Z","created_by":"mallory","updated_by":"mallory"}`,
+				id:   "1",
+			},
+			mocking: func(m *mocks.RealStateService, in input) output {
+				realState := domain.RealState{
+					Registration: 987654321,
+					Address:      "456 Elm St",
+					Size:         200,
+					Price:        domain.NewMoney(27500000),
+					State:        "SP",
+				}
+
+				rs := realState
+				rs.Id = 1
+				rs.CreatedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+				rs.UpdatedAt = time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)
+				rs.CreatedBy = "jane"
+				rs.UpdatedBy = "john"
+
+				m.
+					On("Update", mock.AnythingOfType("context.backgroundCtx"), realState, uint64(1)).
+					Return(rs, nil)
+
+				b, err := json.Marshal(rs)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
 		{
 			name: "When input is invalid, should return 400",
 			input: input{
@@ -532,6 +696,7 @@ func TestUpdate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("PUT", fmt.Sprintf("/realstate/%s", tc.input.id), bytes.NewBuffer([]byte(tc.input.body)))
+			req.Header.Set("Accept", "application/json")
 			router.ServeHTTP(w, req)
 
 			var actual output
@@ -565,7 +730,7 @@ func TestDelete(t *testing.T) {
 				}
 
 				m.
-					On("Delete", mock.AnythingOfType("context.backgroundCtx"), id).
+					On("Delete", mock.AnythingOfType("context.backgroundCtx"), id, uint64(0)).
 					Return(nil)
 
 				return output{
@@ -598,6 +763,33 @@ func TestDelete(t *testing.T) {
 				assert.Equal(t, expected, actual)
 			},
 		},
+		{
+			name:  "when input is valid and real state does not exist, should return 404",
+			input: "1",
+			mocking: func(m *mocks.RealStateService, input string) output {
+				id, err := strconv.ParseUint(input, 10, 64)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				m.
+					On("Delete", mock.AnythingOfType("context.backgroundCtx"), id, uint64(0)).
+					Return(customerrors.NotFound)
+
+				b, err := json.Marshal(customerrors.NotFound)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return output{
+					httpCode: http.StatusNotFound,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
 		{
 			name:  "when input is valid and real state exists, but something goes wrong, should return 500",
 			input: "1",
@@ -608,7 +800,7 @@ func TestDelete(t *testing.T) {
 				}
 
 				m.
-					On("Delete", mock.AnythingOfType("context.backgroundCtx"), id).
+					On("Delete", mock.AnythingOfType("context.backgroundCtx"), id, uint64(0)).
 					Return(customerrors.Internal)
 
 				b, err := json.Marshal(customerrors.Internal)
@@ -635,7 +827,7 @@ func TestDelete(t *testing.T) {
 				}
 
 				m.
-					On("Delete", mock.AnythingOfType("context.backgroundCtx"), id).
+					On("Delete", mock.AnythingOfType("context.backgroundCtx"), id, uint64(0)).
 					Return(fmt.Errorf("unexpected error"))
 
 				b, err := json.Marshal(customerrors.Unexpected)
@@ -668,6 +860,7 @@ func TestDelete(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("DELETE", fmt.Sprintf("/realstate/%s", tc.input), nil)
+			req.Header.Set("Accept", "application/json")
 			router.ServeHTTP(w, req)
 
 			var actual output
@@ -679,3 +872,1209 @@ func TestDelete(t *testing.T) {
 	}
 
 }
+
+func TestList(t *testing.T) {
+	type output struct {
+		httpCode int
+		body     string
+	}
+
+	testCases := []struct {
+		name       string
+		input      string
+		mocking    func(m *mocks.RealStateService) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When no pagination is given, should use the default page and link to the next one",
+			input: "",
+			mocking: func(m *mocks.RealStateService) output {
+				page := ports.Page{Limit: ports.DefaultPageLimit, Offset: 0}
+				realStates := []domain.RealState{
+					{
+						Id:           1,
+						Registration: 987654321,
+						Address:      "456 Elm St",
+						Size:         200,
+						Price:        domain.NewMoney(25000050),
+						State:        "CA",
+					},
+				}
+
+				m.
+					On("List", mock.AnythingOfType("context.backgroundCtx"), page).
+					Return(ports.RealStatePage{RealStates: realStates, Total: 25, Page: page}, nil)
+
+				b, err := json.Marshal(map[string]any{
+					"data":   realStates,
+					"total":  25,
+					"limit":  20,
+					"offset": 0,
+					"links": map[string]string{
+						"next": "/realstate/?limit=20&offset=20",
+					},
+				})
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.JSONEq(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When the last page is requested, should link only to the previous one",
+			input: "?limit=10&offset=20",
+			mocking: func(m *mocks.RealStateService) output {
+				page := ports.Page{Limit: 10, Offset: 20}
+
+				m.
+					On("List", mock.AnythingOfType("context.backgroundCtx"), page).
+					Return(ports.RealStatePage{RealStates: nil, Total: 25, Page: page}, nil)
+
+				b, err := json.Marshal(map[string]any{
+					"data":   []domain.RealState{},
+					"total":  25,
+					"limit":  10,
+					"offset": 20,
+					"links": map[string]string{
+						"prev": "/realstate/?limit=10&offset=10",
+					},
+				})
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.JSONEq(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When limit is above the maximum, should return 400",
+			input: "?limit=1000",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When offset is invalid, should return 400",
+			input: "?offset=a",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When offset is beyond what the database accepts, should return 400",
+			input: "?offset=18446744073709551615",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When service fails, should return 500",
+			input: "",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("List", mock.AnythingOfType("context.backgroundCtx"), ports.Page{Limit: ports.DefaultPageLimit, Offset: 0}).
+					Return(ports.RealStatePage{}, customerrors.Internal)
+
+				b, err := json.Marshal(customerrors.Internal)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusInternalServerError,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+			expected := tc.mocking(s)
+
+			hdl := realstatehdlr.NewRealStateHandler(s)
+			hdl.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/realstate/%s", tc.input), nil)
+			req.Header.Set("Accept", "application/json")
+			router.ServeHTTP(w, req)
+
+			var actual output
+			actual.httpCode = w.Code
+			actual.body = w.Body.String()
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	type output struct {
+		httpCode int
+		body     string
+	}
+
+	testCases := []struct {
+		name       string
+		input      string
+		mocking    func(m *mocks.RealStateService) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When filters and sort are valid, should search with a typed query",
+			input: "?state=sp,RJ&state=mg&min_price=100000&max_price=300000.50&min_size=50&max_size=300&registration=987654321&address=Elm&sort=price,-size&limit=10",
+			mocking: func(m *mocks.RealStateService) output {
+				minPrice, maxPrice := domain.NewMoney(10000000), domain.NewMoney(30000050)
+				minSize, maxSize := uint64(50), uint64(300)
+				registration := uint64(987654321)
+
+				query := ports.RealStateQuery{
+					Filter: ports.RealStateFilter{
+						States:       []string{"SP", "RJ", "MG"},
+						MinPrice:     &minPrice,
+						MaxPrice:     &maxPrice,
+						MinSize:      &minSize,
+						MaxSize:      &maxSize,
+						Registration: &registration,
+						Address:      "Elm",
+					},
+					Sort: []ports.Sort{
+						{Field: ports.SortByPrice},
+						{Field: ports.SortBySize, Desc: true},
+					},
+					Page: ports.Page{Limit: 10, Offset: 0},
+				}
+
+				realStates := []domain.RealState{
+					{
+						Id:           1,
+						Registration: 987654321,
+						Address:      "456 Elm St",
+						Size:         200,
+						Price:        domain.NewMoney(25000050),
+						State:        "SP",
+					},
+				}
+
+				m.
+					On("Search", mock.AnythingOfType("context.backgroundCtx"), query).
+					Return(ports.RealStatePage{RealStates: realStates, Total: 1, Page: query.Page}, nil)
+
+				b, err := json.Marshal(map[string]any{
+					"data":   realStates,
+					"total":  1,
+					"limit":  10,
+					"offset": 0,
+					"links":  map[string]string{},
+				})
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.JSONEq(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When there are more results, should keep the filters in the next link",
+			input: "?state=SP&limit=1",
+			mocking: func(m *mocks.RealStateService) output {
+				query := ports.RealStateQuery{
+					Filter: ports.RealStateFilter{States: []string{"SP"}},
+					Page:   ports.Page{Limit: 1, Offset: 0},
+				}
+
+				m.
+					On("Search", mock.AnythingOfType("context.backgroundCtx"), query).
+					Return(ports.RealStatePage{RealStates: nil, Total: 2, Page: query.Page}, nil)
+
+				b, err := json.Marshal(map[string]any{
+					"data":   []domain.RealState{},
+					"total":  2,
+					"limit":  1,
+					"offset": 0,
+					"links": map[string]string{
+						"next": "/realstate/search?limit=1&offset=1&state=SP",
+					},
+				})
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.JSONEq(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When state is not a brazilian state code, should return 400",
+			input: "?state=XYZ",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When min price is greater than max price, should return 400",
+			input: "?min_price=10&max_price=5",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When registration does not fit the registration column, should return 400",
+			input: "?registration=18446744073709551615",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When size does not fit the size column, should return 400",
+			input: "?max_size=100000000",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When sort field is not sortable, should return 400",
+			input: "?sort=address",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When sort field is repeated, should return 400",
+			input: "?sort=price,-price",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When service fails, should return 500",
+			input: "?state=SP",
+			mocking: func(m *mocks.RealStateService) output {
+				query := ports.RealStateQuery{
+					Filter: ports.RealStateFilter{States: []string{"SP"}},
+					Page:   ports.Page{Limit: ports.DefaultPageLimit, Offset: 0},
+				}
+
+				m.
+					On("Search", mock.AnythingOfType("context.backgroundCtx"), query).
+					Return(ports.RealStatePage{}, customerrors.Internal)
+
+				b, err := json.Marshal(customerrors.Internal)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusInternalServerError,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+			expected := tc.mocking(s)
+
+			hdl := realstatehdlr.NewRealStateHandler(s)
+			hdl.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/realstate/search%s", tc.input), nil)
+			req.Header.Set("Accept", "application/json")
+			router.ServeHTTP(w, req)
+
+			var actual output
+			actual.httpCode = w.Code
+			actual.body = w.Body.String()
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
+
+func TestListAfter(t *testing.T) {
+	type output struct {
+		httpCode int
+		body     string
+	}
+
+	secret := []byte("secret")
+
+	token := func(t *testing.T, secret []byte, field string, desc bool, id uint64, value string) string {
+		c, err := cursor.Encode(secret, struct {
+			Field string `json:"f"`
+			Desc  bool   `json:"d,omitempty"`
+			Id    uint64 `json:"i"`
+			Value string `json:"v,omitempty"`
+		}{field, desc, id, value})
+		assert.NoError(t, err)
+
+		return c
+	}
+
+	realStates := []domain.RealState{
+		{
+			Id:           1,
+			Registration: 987654321,
+			Address:      "456 Elm St",
+			Size:         200,
+			Price:        domain.NewMoney(25000050),
+			State:        "SP",
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		input      func(t *testing.T) string
+		mocking    func(m *mocks.RealStateService) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name: "When cursor is empty, should start from the beginning and return a signed next cursor",
+			input: func(t *testing.T) string {
+				return "?cursor=&sort=-price&limit=1"
+			},
+			mocking: func(m *mocks.RealStateService) output {
+				sort := ports.Sort{Field: ports.SortByPrice, Desc: true}
+
+				m.
+					On("ListAfter", mock.AnythingOfType("context.backgroundCtx"), ports.KeysetQuery{Sort: sort, Limit: 1}).
+					Return(ports.RealStateKeysetPage{
+						RealStates: realStates,
+						Next:       &ports.Cursor{Sort: sort, LastId: 1, LastValue: "250000.5"},
+						Limit:      1,
+					}, nil)
+
+				next := token(t, secret, "price", true, 1, "250000.5")
+
+				b, err := json.Marshal(map[string]any{
+					"data":        realStates,
+					"limit":       1,
+					"next_cursor": next,
+					"links": map[string]string{
+						"next": "/realstate/?" + url.Values{"cursor": {next}, "limit": {"1"}, "sort": {"-price"}}.Encode(),
+					},
+				})
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.JSONEq(t, expected.body, actual.body)
+			},
+		},
+		{
+			name: "When cursor is valid, should continue after it using its ordering",
+			input: func(t *testing.T) string {
+				return "?cursor=" + token(t, secret, "price", false, 1, "250000.5")
+			},
+			mocking: func(m *mocks.RealStateService) output {
+				sort := ports.Sort{Field: ports.SortByPrice}
+
+				m.
+					On("ListAfter", mock.AnythingOfType("context.backgroundCtx"), ports.KeysetQuery{
+						Sort:  sort,
+						After: &ports.Cursor{Sort: sort, LastId: 1, LastValue: "250000.5"},
+						Limit: ports.DefaultPageLimit,
+					}).
+					Return(ports.RealStateKeysetPage{RealStates: nil, Limit: ports.DefaultPageLimit}, nil)
+
+				b, err := json.Marshal(map[string]any{
+					"data":  []domain.RealState{},
+					"limit": ports.DefaultPageLimit,
+					"links": map[string]string{},
+				})
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.JSONEq(t, expected.body, actual.body)
+			},
+		},
+		{
+			name: "When cursor was signed with another secret, should return 400",
+			input: func(t *testing.T) string {
+				return "?cursor=" + token(t, []byte("forged"), "id", false, 1, "")
+			},
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When sort differs from the cursor ordering, should return 400",
+			input: func(t *testing.T) string {
+				return "?sort=size&cursor=" + token(t, secret, "price", false, 1, "250000.5")
+			},
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When offset is combined with a cursor, should return 400",
+			input: func(t *testing.T) string {
+				return "?cursor=&offset=10"
+			},
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When service fails, should return 500",
+			input: func(t *testing.T) string {
+				return "?cursor="
+			},
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("ListAfter", mock.AnythingOfType("context.backgroundCtx"), ports.KeysetQuery{Sort: ports.Sort{Field: ports.SortById}, Limit: ports.DefaultPageLimit}).
+					Return(ports.RealStateKeysetPage{}, customerrors.Internal)
+
+				b, err := json.Marshal(customerrors.Internal)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusInternalServerError,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+			expected := tc.mocking(s)
+
+			hdl := realstatehdlr.NewRealStateHandler(s)
+			hdl.CursorSecret = secret
+			hdl.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/realstate/%s", tc.input(t)), nil)
+			req.Header.Set("Accept", "application/json")
+			router.ServeHTTP(w, req)
+
+			var actual output
+			actual.httpCode = w.Code
+			actual.body = w.Body.String()
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	type input struct {
+		id          string
+		contentType string
+		ifMatch     string
+		body        string
+	}
+
+	type output struct {
+		httpCode int
+		header   http.Header
+		body     string
+	}
+
+	patched := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(26000000),
+		State:        "SP",
+		Version:      4,
+	}
+
+	testCases := []struct {
+		name       string
+		input      input
+		mocking    func(m *mocks.RealStateService, in input) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When body is a merge patch, should hand it to the service and return the patched real state",
+			input: input{id: "1", contentType: "application/merge-patch+json", body: `{"price":260000}`},
+			mocking: func(m *mocks.RealStateService, in input) output {
+				m.
+					On("Patch", mock.AnythingOfType("context.backgroundCtx"), ports.RealStatePatch{Format: ports.MergePatch, Document: []byte(in.body)}, uint64(1)).
+					Return(patched, nil)
+
+				b, err := json.Marshal(patched)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusOK,
+					header:   http.Header{"Etag": []string{`"4"`}},
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.Equal(t, expected.header.Get("ETag"), actual.header.Get("ETag"))
+				assert.Equal(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When body is a json patch with If-Match, should pass the expected version along",
+			input: input{id: "1", contentType: "application/json-patch+json; charset=utf-8", ifMatch: `"3"`, body: `[{"op":"replace","path":"/price","value":260000}]`},
+			mocking: func(m *mocks.RealStateService, in input) output {
+				m.
+					On("Patch", mock.AnythingOfType("context.backgroundCtx"), ports.RealStatePatch{Format: ports.JSONPatch, Document: []byte(in.body), Version: 3}, uint64(1)).
+					Return(patched, nil)
+
+				b, err := json.Marshal(patched)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusOK,
+					header:   http.Header{"Etag": []string{`"4"`}},
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.Equal(t, expected.header.Get("ETag"), actual.header.Get("ETag"))
+				assert.Equal(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When content type is plain json, should return 415 advertising the patch formats",
+			input: input{id: "1", contentType: "application/json", body: `{"price":260000}`},
+			mocking: func(m *mocks.RealStateService, in input) output {
+				b, err := json.Marshal(customerrors.UnsupportedMediaType)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusUnsupportedMediaType,
+					header:   http.Header{"Accept-Patch": []string{"application/merge-patch+json, application/json-patch+json"}},
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.Equal(t, expected.header.Get("Accept-Patch"), actual.header.Get("Accept-Patch"))
+				assert.Equal(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When patched real state fails validation, should return 400 listing the fields",
+			input: input{id: "1", contentType: "application/merge-patch+json", body: `{"state":"XX"}`},
+			mocking: func(m *mocks.RealStateService, in input) output {
+				m.
+					On("Patch", mock.AnythingOfType("context.backgroundCtx"), ports.RealStatePatch{Format: ports.MergePatch, Document: []byte(in.body)}, uint64(1)).
+					Return(domain.RealState{}, domain.ValidationError{Fields: []domain.FieldError{
+						{Field: "state", Rule: domain.RuleState, Message: "must be a brazilian state code"},
+					}})
+
+				b, err := json.Marshal(customerrors.Validation.WithDetails(
+					customerrors.Detail{Field: "state", Rule: domain.RuleState, Message: "must be a brazilian state code"},
+				))
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.Equal(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When patch cannot be applied, should return 422",
+			input: input{id: "1", contentType: "application/json-patch+json", body: `[{"op":"remove","path":"/rooms"}]`},
+			mocking: func(m *mocks.RealStateService, in input) output {
+				m.
+					On("Patch", mock.AnythingOfType("context.backgroundCtx"), ports.RealStatePatch{Format: ports.JSONPatch, Document: []byte(in.body)}, uint64(1)).
+					Return(domain.RealState{}, customerrors.Unprocessable)
+
+				b, err := json.Marshal(customerrors.Unprocessable)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusUnprocessableEntity,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.Equal(t, expected.body, actual.body)
+			},
+		},
+		{
+			name:  "When id is invalid, should return 400",
+			input: input{id: "a", contentType: "application/merge-patch+json", body: `{}`},
+			mocking: func(m *mocks.RealStateService, in input) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{
+					httpCode: http.StatusBadRequest,
+					body:     string(b),
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.httpCode, actual.httpCode)
+				assert.Equal(t, expected.body, actual.body)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+
+			expected := tc.mocking(s, tc.input)
+
+			hdlr := realstatehdlr.NewRealStateHandler(s)
+			hdlr.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PATCH", fmt.Sprintf("/realstate/%s", tc.input.id), bytes.NewBufferString(tc.input.body))
+			req.Header.Set("Accept", "application/json")
+			req.Header.Set("Content-Type", tc.input.contentType)
+			if tc.input.ifMatch != "" {
+				req.Header.Set("If-Match", tc.input.ifMatch)
+			}
+			router.ServeHTTP(w, req)
+
+			actual := output{
+				httpCode: w.Code,
+				header:   w.Header(),
+				body:     w.Body.String(),
+			}
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	type output struct {
+		httpCode int
+		etag     string
+		body     string
+	}
+
+	restored := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(25000000),
+		State:        "SP",
+		Version:      5,
+	}
+
+	testCases := []struct {
+		name       string
+		input      string
+		mocking    func(m *mocks.RealStateService) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When real state was deleted, should return it restored with its ETag",
+			input: "1",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Restore", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).
+					Return(restored, nil)
+
+				b, err := json.Marshal(restored)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusOK, etag: `"5"`, body: string(b)}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When no deleted real state has the id, should return 404",
+			input: "1",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Restore", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).
+					Return(domain.RealState{}, customerrors.NotFound)
+
+				b, err := json.Marshal(customerrors.NotFound)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusNotFound, body: string(b)}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When id is invalid, should return 400",
+			input: "a",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusBadRequest, body: string(b)}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+
+			expected := tc.mocking(s)
+
+			hdlr := realstatehdlr.NewRealStateHandler(s)
+			hdlr.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", fmt.Sprintf("/realstate/%s/restore", tc.input), nil)
+			req.Header.Set("Accept", "application/json")
+			router.ServeHTTP(w, req)
+
+			actual := output{
+				httpCode: w.Code,
+				etag:     w.Header().Get("ETag"),
+				body:     w.Body.String(),
+			}
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	type output struct {
+		httpCode int
+		body     string
+	}
+
+	testCases := []struct {
+		name       string
+		mocking    func(m *mocks.RealStateService) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name: "When service purges, should return how many real states were removed",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Purge", mock.AnythingOfType("context.backgroundCtx")).
+					Return(uint64(2), nil)
+
+				return output{httpCode: http.StatusOK, body: `{"purged":2}`}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When caller is not an admin, should return 403",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Purge", mock.AnythingOfType("context.backgroundCtx")).
+					Return(uint64(0), customerrors.Forbidden)
+
+				b, err := json.Marshal(customerrors.Forbidden)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusForbidden, body: string(b)}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+
+			expected := tc.mocking(s)
+
+			hdlr := realstatehdlr.NewRealStateHandler(s)
+			hdlr.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/realstate/purge", nil)
+			req.Header.Set("Accept", "application/json")
+			router.ServeHTTP(w, req)
+
+			actual := output{
+				httpCode: w.Code,
+				body:     w.Body.String(),
+			}
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	type output struct {
+		httpCode int
+		body     string
+	}
+
+	entries := []domain.AuditEntry{
+		{
+			Id:          2,
+			RealStateId: 42,
+			Operation:   domain.AuditUpdate,
+			Actor:       "john",
+			At:          time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC),
+			Changes: []domain.FieldChange{
+				{Field: "price", Before: json.RawMessage(`250000.00`), After: json.RawMessage(`260000.00`)},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		input      string
+		mocking    func(m *mocks.RealStateService) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When real state has changes, should return the page with links",
+			input: "/realstate/42/history?limit=1&offset=1",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("History", mock.AnythingOfType("context.backgroundCtx"), uint64(42), ports.Page{Limit: 1, Offset: 1}).
+					Return(ports.AuditPage{Entries: entries, Total: 3, Page: ports.Page{Limit: 1, Offset: 1}}, nil)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     `{"data":[{"id":2,"real_state_id":42,"operation":"update","actor":"john","at":"2024-05-02T09:30:00Z","changes":[{"field":"price","before":250000.00,"after":260000.00}]}],"total":3,"limit":1,"offset":1,"links":{"next":"/realstate/42/history?limit=1\u0026offset=2","prev":"/realstate/42/history?limit=1\u0026offset=0"}}`,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When real state has no changes, should return an empty list",
+			input: "/realstate/42/history",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("History", mock.AnythingOfType("context.backgroundCtx"), uint64(42), ports.Page{Limit: ports.DefaultPageLimit}).
+					Return(ports.AuditPage{Page: ports.Page{Limit: ports.DefaultPageLimit}}, nil)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     `{"data":[],"total":0,"limit":20,"offset":0,"links":{}}`,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When limit is invalid, should return 400",
+			input: "/realstate/42/history?limit=0",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusBadRequest, body: string(b)}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+
+			expected := tc.mocking(s)
+
+			hdlr := realstatehdlr.NewRealStateHandler(s)
+			hdlr.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tc.input, nil)
+			req.Header.Set("Accept", "application/json")
+			router.ServeHTTP(w, req)
+
+			actual := output{
+				httpCode: w.Code,
+				body:     w.Body.String(),
+			}
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
+
+func TestPrices(t *testing.T) {
+	type output struct {
+		httpCode int
+		body     string
+	}
+
+	history := domain.PriceHistory{
+		RealStateId: 1,
+		Prices: []domain.PricePoint{
+			{Price: domain.NewMoney(25000000), At: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+			{Price: domain.NewMoney(26000000), At: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)},
+		},
+		ListingPrice:  domain.NewMoney(25000000),
+		CurrentPrice:  domain.NewMoney(26000000),
+		Change:        domain.NewMoney(1000000),
+		ChangePercent: 4,
+	}
+
+	testCases := []struct {
+		name       string
+		input      string
+		mocking    func(m *mocks.RealStateService) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When real state exists, should return its price series and change",
+			input: "1",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Prices", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).
+					Return(history, nil)
+
+				return output{
+					httpCode: http.StatusOK,
+					body:     `{"real_state_id":1,"prices":[{"price":250000.00,"at":"2024-05-01T12:00:00Z"},{"price":260000.00,"at":"2024-06-01T12:00:00Z"}],"listing_price":250000.00,"current_price":260000.00,"change":10000.00,"change_percent":4}`,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When real state does not exist, should return 404",
+			input: "1",
+			mocking: func(m *mocks.RealStateService) output {
+				m.
+					On("Prices", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).
+					Return(domain.PriceHistory{}, customerrors.NotFound)
+
+				b, err := json.Marshal(customerrors.NotFound)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusNotFound, body: string(b)}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When id is invalid, should return 400",
+			input: "a",
+			mocking: func(m *mocks.RealStateService) output {
+				b, err := json.Marshal(customerrors.BadRequest)
+				assert.NoError(t, err)
+
+				return output{httpCode: http.StatusBadRequest, body: string(b)}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+
+			s := mocks.NewRealStateService(t)
+
+			expected := tc.mocking(s)
+
+			hdlr := realstatehdlr.NewRealStateHandler(s)
+			hdlr.BuildRoutes(router)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/realstate/%s/prices", tc.input), nil)
+			req.Header.Set("Accept", "application/json")
+			router.ServeHTTP(w, req)
+
+			actual := output{
+				httpCode: w.Code,
+				body:     w.Body.String(),
+			}
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
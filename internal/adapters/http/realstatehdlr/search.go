@@ -0,0 +1,163 @@
+package realstatehdlr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+const maxAddressFilterLength = 255
+
+func parseQuery(c *gin.Context) (ports.RealStateQuery, error) {
+	var query ports.RealStateQuery
+
+	page, err := parsePage(c)
+	if err != nil {
+		return ports.RealStateQuery{}, err
+	}
+	query.Page = page
+
+	filter, err := parseFilter(c)
+	if err != nil {
+		return ports.RealStateQuery{}, err
+	}
+	query.Filter = filter
+
+	sort, err := parseSort(c.Query("sort"))
+	if err != nil {
+		return ports.RealStateQuery{}, err
+	}
+	query.Sort = sort
+
+	return query, nil
+}
+
+func parseFilter(c *gin.Context) (ports.RealStateFilter, error) {
+	var (
+		filter ports.RealStateFilter
+		err    error
+	)
+
+	for _, value := range c.QueryArray("state") {
+		for _, state := range strings.Split(value, ",") {
+			state = strings.ToUpper(strings.TrimSpace(state))
+			if !domain.IsBrazilianState(state) {
+				return ports.RealStateFilter{}, customerrors.BadRequest
+			}
+
+			filter.States = append(filter.States, state)
+		}
+	}
+
+	if filter.MinPrice, err = parsePrice(c, "min_price"); err != nil {
+		return ports.RealStateFilter{}, err
+	}
+
+	if filter.MaxPrice, err = parsePrice(c, "max_price"); err != nil {
+		return ports.RealStateFilter{}, err
+	}
+
+	if filter.MinPrice != nil && filter.MaxPrice != nil && filter.MinPrice.Cmp(*filter.MaxPrice) > 0 {
+		return ports.RealStateFilter{}, customerrors.BadRequest
+	}
+
+	if filter.MinSize, err = parseUintQuery(c, "min_size", domain.MaxSize); err != nil {
+		return ports.RealStateFilter{}, err
+	}
+
+	if filter.MaxSize, err = parseUintQuery(c, "max_size", domain.MaxSize); err != nil {
+		return ports.RealStateFilter{}, err
+	}
+
+	if filter.MinSize != nil && filter.MaxSize != nil && *filter.MinSize > *filter.MaxSize {
+		return ports.RealStateFilter{}, customerrors.BadRequest
+	}
+
+	if filter.Registration, err = parseUintQuery(c, "registration", domain.MaxRegistration); err != nil {
+		return ports.RealStateFilter{}, err
+	}
+
+	if address, ok := c.GetQuery("address"); ok {
+		address = strings.TrimSpace(address)
+		if address == "" || len(address) > maxAddressFilterLength {
+			return ports.RealStateFilter{}, customerrors.BadRequest
+		}
+
+		filter.Address = address
+	}
+
+	return filter, nil
+}
+
+func parseSort(value string) ([]ports.Sort, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var sort []ports.Sort
+	seen := make(map[ports.SortField]bool)
+
+	for _, term := range strings.Split(value, ",") {
+		term = strings.TrimSpace(term)
+
+		s := ports.Sort{}
+		if strings.HasPrefix(term, "-") {
+			s.Desc = true
+			term = term[1:]
+		}
+		s.Field = ports.SortField(term)
+
+		if !isSortable(s.Field) || seen[s.Field] {
+			return nil, customerrors.BadRequest
+		}
+		seen[s.Field] = true
+
+		sort = append(sort, s)
+	}
+
+	return sort, nil
+}
+
+func parsePrice(c *gin.Context, key string) (*domain.Money, error) {
+	value, ok := c.GetQuery(key)
+	if !ok {
+		return nil, nil
+	}
+
+	price, err := domain.ParseMoney(value)
+	if err != nil || price.IsNegative() {
+		return nil, customerrors.BadRequest
+	}
+
+	return &price, nil
+}
+
+// parseUintQuery reads key as a whole number no greater than max, the largest
+// value the column it filters holds.
+func parseUintQuery(c *gin.Context, key string, max uint64) (*uint64, error) {
+	value, ok := c.GetQuery(key)
+	if !ok {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || n > max {
+		return nil, customerrors.BadRequest
+	}
+
+	return &n, nil
+}
+
+func isSortable(field ports.SortField) bool {
+	for _, f := range ports.SortableFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+)
+
+const (
+	CreateAuditEntry  = `INSERT INTO real_state_audit (real_state_id, real_state_audit_operation, real_state_audit_actor, real_state_audit_at, real_state_audit_changes) VALUES (?, ?, ?, ?, ?)`
+	ListAuditEntries  = `SELECT real_state_audit_id, real_state_id, real_state_audit_operation, real_state_audit_actor, real_state_audit_at, real_state_audit_changes FROM real_state_audit WHERE real_state_id = ? ORDER BY real_state_audit_id DESC LIMIT ? OFFSET ?`
+	CountAuditEntries = `SELECT COUNT(*) FROM real_state_audit WHERE real_state_id = ?`
+)
+
+// auditRepository reads the audit trail, which the real state repository
+// writes along with every change.
+type auditRepository struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+// NewAuditRepository serves both MySQL and SQLite, whose audit queries are
+// the same.
+func NewAuditRepository(db *sql.DB) *auditRepository {
+	return &auditRepository{
+		db:      db,
+		dialect: mysqlDialect,
+	}
+}
+
+func NewPostgresAuditRepository(db *sql.DB) *auditRepository {
+	return &auditRepository{
+		db:      db,
+		dialect: postgresDialect,
+	}
+}
+
+// ListAudit returns the entries of a real state newest first, along with how
+// many there are in total.
+func (r *auditRepository) ListAudit(ctx context.Context, realStateId uint64, page ports.Page) ([]domain.AuditEntry, uint64, error) {
+	var total uint64
+
+	if err := r.db.QueryRowContext(ctx, r.dialect.bind(CountAuditEntries), realStateId).Scan(&total); err != nil {
+		return nil, 0, internalError(ctx, "ListAudit", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(ListAuditEntries), realStateId, page.Limit, page.Offset)
+	if err != nil {
+		return nil, 0, internalError(ctx, "ListAudit", err)
+	}
+	defer rows.Close()
+
+	entries := make([]domain.AuditEntry, 0, page.Limit)
+	for rows.Next() {
+		var (
+			entry   domain.AuditEntry
+			changes []byte
+		)
+
+		if err := rows.Scan(&entry.Id, &entry.RealStateId, &entry.Operation, &entry.Actor, &entry.At, &changes); err != nil {
+			return nil, 0, internalError(ctx, "ListAudit", err)
+		}
+
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, 0, internalError(ctx, "ListAudit", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, internalError(ctx, "ListAudit", err)
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,135 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/repository"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+var auditColumns = []string{
+	"real_state_audit_id", "real_state_id", "real_state_audit_operation", "real_state_audit_actor", "real_state_audit_at", "real_state_audit_changes",
+}
+
+func TestListAudit(t *testing.T) {
+	type output struct {
+		entries []domain.AuditEntry
+		total   uint64
+		err     error
+	}
+
+	testCases := []struct {
+		name       string
+		input      ports.Page
+		mocking    func(mock sqlmock.Sqlmock, page ports.Page) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When real state has entries, should return the page newest first with the total",
+			input: ports.Page{Limit: 2, Offset: 0},
+			mocking: func(mock sqlmock.Sqlmock, page ports.Page) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.CountAuditEntries)).
+					WithArgs(42).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.ListAuditEntries)).
+					WithArgs(42, page.Limit, page.Offset).
+					WillReturnRows(sqlmock.NewRows(auditColumns).
+						AddRow(3, 42, "delete", "john", updatedAt, `[{"field": "state", "before": "SP"}]`).
+						AddRow(2, 42, "update", "john", updatedAt, `[{"field": "price", "after": 260000.00, "before": 250000.00}]`))
+
+				return output{
+					entries: []domain.AuditEntry{
+						{
+							Id:          3,
+							RealStateId: 42,
+							Operation:   domain.AuditDelete,
+							Actor:       "john",
+							At:          updatedAt,
+							Changes:     []domain.FieldChange{{Field: "state", Before: json.RawMessage(`"SP"`)}},
+						},
+						{
+							Id:          2,
+							RealStateId: 42,
+							Operation:   domain.AuditUpdate,
+							Actor:       "john",
+							At:          updatedAt,
+							Changes:     []domain.FieldChange{{Field: "price", Before: json.RawMessage(`250000.00`), After: json.RawMessage(`260000.00`)}},
+						},
+					},
+					total: 3,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When changes are not valid JSON, should return error",
+			input: ports.Page{Limit: 20, Offset: 0},
+			mocking: func(mock sqlmock.Sqlmock, page ports.Page) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.CountAuditEntries)).
+					WithArgs(42).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.ListAuditEntries)).
+					WithArgs(42, page.Limit, page.Offset).
+					WillReturnRows(sqlmock.NewRows(auditColumns).
+						AddRow(1, 42, "create", "jane", createdAt, `not json`))
+
+				return output{err: customerrors.Internal}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When count fails, should return error",
+			input: ports.Page{Limit: 20, Offset: 0},
+			mocking: func(mock sqlmock.Sqlmock, page ports.Page) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.CountAuditEntries)).
+					WithArgs(42).
+					WillReturnError(errors.New("count failed"))
+
+				return output{err: customerrors.Internal}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock, tc.input)
+
+			r := repository.NewAuditRepository(db)
+			var actual output
+			actual.entries, actual.total, actual.err = r.ListAudit(ctx, 42, tc.input)
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	mysqlDuplicateEntry     = 1062
+	postgresUniqueViolation = "23505"
+)
+
+// dialect holds what differs between the databases the repositories run on.
+// Queries are written once, for MySQL, and adapted through it.
+type dialect struct {
+	// forUpdate is appended to the lock queries to hold the row until the
+	// transaction ends.
+	forUpdate string
+	// numbered placeholders ($1, $2, ...) replace "?".
+	numbered bool
+	// returning reads generated ids with RETURNING, for drivers without
+	// LastInsertId.
+	returning bool
+	// cents stores prices as INTEGER cents, where the database has no exact
+	// decimal type.
+	cents            bool
+	isDuplicateEntry func(err error) bool
+}
+
+var (
+	mysqlDialect = dialect{
+		forUpdate:        " FOR UPDATE",
+		isDuplicateEntry: isMySQLDuplicateEntry,
+	}
+
+	// SQLite has no row locks; transactions lock the whole database instead.
+	sqliteDialect = dialect{
+		cents:            true,
+		isDuplicateEntry: isSQLiteDuplicateEntry,
+	}
+
+	postgresDialect = dialect{
+		forUpdate:        " FOR UPDATE",
+		numbered:         true,
+		returning:        true,
+		isDuplicateEntry: isPostgresDuplicateEntry,
+	}
+)
+
+// bind rewrites the "?" placeholders of query when the dialect numbers them.
+// Question marks inside string literals are left alone.
+func (d dialect) bind(query string) string {
+	if !d.numbered {
+		return query
+	}
+
+	var (
+		b       strings.Builder
+		n       int
+		literal bool
+	)
+
+	for _, c := range query {
+		switch {
+		case c == '\'':
+			literal = !literal
+		case c == '?' && !literal:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
+
+// price returns the value stored in the price columns for m.
+func (d dialect) price(m domain.Money) any {
+	if d.cents {
+		return m.Amount()
+	}
+
+	return m
+}
+
+// args replaces the prices among args by their stored value.
+func (d dialect) args(args []any) []any {
+	for i, arg := range args {
+		if m, ok := arg.(domain.Money); ok {
+			args[i] = d.price(m)
+		}
+	}
+
+	return args
+}
+
+// scanPrice returns the scan destination reading a price column into m.
+func (d dialect) scanPrice(m *domain.Money) any {
+	if d.cents {
+		return centsScanner{m}
+	}
+
+	return m
+}
+
+func (d dialect) scanDest(realState *domain.RealState) []any {
+	return []any{
+		&realState.Id, &realState.Registration, &realState.Address, wholeNumberScanner{&realState.Size}, d.scanPrice(&realState.Price), &realState.State,
+		&realState.CreatedAt, &realState.UpdatedAt, &realState.CreatedBy, &realState.UpdatedBy, &realState.Version,
+	}
+}
+
+type centsScanner struct {
+	m *domain.Money
+}
+
+func (s centsScanner) Scan(src any) error {
+	cents, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into cents", src)
+	}
+
+	*s.m = domain.NewMoney(cents)
+	return nil
+}
+
+// wholeNumberScanner reads a DECIMAL column holding a whole number, which
+// drivers return as text with its decimal places, such as "200.00".
+type wholeNumberScanner struct {
+	n *uint64
+}
+
+func (s wholeNumberScanner) Scan(src any) error {
+	var value string
+
+	switch v := src.(type) {
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case uint64:
+		value = strconv.FormatUint(v, 10)
+	default:
+		return fmt.Errorf("cannot scan %T into a whole number", src)
+	}
+
+	whole, frac, _ := strings.Cut(value, ".")
+	if strings.Trim(frac, "0") != "" {
+		return fmt.Errorf("cannot scan %q into a whole number", value)
+	}
+
+	n, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*s.n = n
+	return nil
+}
+
+func isMySQLDuplicateEntry(err error) bool {
+	var merr *mysql.MySQLError
+
+	return errors.As(err, &merr) && merr.Number == mysqlDuplicateEntry
+}
+
+func isSQLiteDuplicateEntry(err error) bool {
+	var serr *sqlite.Error
+
+	return errors.As(err, &serr) && serr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+func isPostgresDuplicateEntry(err error) bool {
+	var perr *pq.Error
+
+	return errors.As(err, &perr) && perr.Code == postgresUniqueViolation
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/natanchagas/gin-crud/internal/pkg/logging"
+)
+
+// internalError logs the driver error behind a failed operation with the
+// logger of ctx, then hides it from callers behind customerrors.Internal.
+func internalError(ctx context.Context, operation string, err error) error {
+	logging.FromContext(ctx).ErrorContext(ctx, "repository operation failed", "operation", operation, "error", err)
+
+	return customerrors.Internal
+}
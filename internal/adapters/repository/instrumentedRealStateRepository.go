@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+// instrumentedRealStateRepository observes the latency of every call to the
+// repository it wraps and counts the errors it returns by method and error
+// code.
+type instrumentedRealStateRepository struct {
+	next     ports.RealStateRepository
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+func NewInstrumentedRealStateRepository(next ports.RealStateRepository, registerer prometheus.Registerer) *instrumentedRealStateRepository {
+	r := &instrumentedRealStateRepository{
+		next: next,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "realstate_repository_duration_seconds",
+			Help:    "Latency of real state repository calls, by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "realstate_repository_errors_total",
+			Help: "Errors returned by real state repository calls, by method and error code.",
+		}, []string{"method", "code"}),
+	}
+
+	registerer.MustRegister(r.duration, r.errors)
+
+	return r
+}
+
+func (r *instrumentedRealStateRepository) CreateRealState(ctx context.Context, realState domain.RealState) (domain.RealState, error) {
+	defer r.observe("CreateRealState", time.Now())
+	created, err := r.next.CreateRealState(ctx, realState)
+	return created, r.fail("CreateRealState", err)
+}
+
+func (r *instrumentedRealStateRepository) GetRealState(ctx context.Context, id uint64) (domain.RealState, error) {
+	defer r.observe("GetRealState", time.Now())
+	realState, err := r.next.GetRealState(ctx, id)
+	return realState, r.fail("GetRealState", err)
+}
+
+func (r *instrumentedRealStateRepository) ListRealStates(ctx context.Context, query ports.RealStateQuery) ([]domain.RealState, uint64, error) {
+	defer r.observe("ListRealStates", time.Now())
+	realStates, total, err := r.next.ListRealStates(ctx, query)
+	return realStates, total, r.fail("ListRealStates", err)
+}
+
+func (r *instrumentedRealStateRepository) ListRealStatesAfter(ctx context.Context, query ports.KeysetQuery) ([]domain.RealState, error) {
+	defer r.observe("ListRealStatesAfter", time.Now())
+	realStates, err := r.next.ListRealStatesAfter(ctx, query)
+	return realStates, r.fail("ListRealStatesAfter", err)
+}
+
+func (r *instrumentedRealStateRepository) UpdateRealState(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error) {
+	defer r.observe("UpdateRealState", time.Now())
+	updated, err := r.next.UpdateRealState(ctx, realState, id)
+	return updated, r.fail("UpdateRealState", err)
+}
+
+func (r *instrumentedRealStateRepository) DeleteRealState(ctx context.Context, id uint64, version uint64) error {
+	defer r.observe("DeleteRealState", time.Now())
+	return r.fail("DeleteRealState", r.next.DeleteRealState(ctx, id, version))
+}
+
+func (r *instrumentedRealStateRepository) RestoreRealState(ctx context.Context, id uint64) (domain.RealState, error) {
+	defer r.observe("RestoreRealState", time.Now())
+	realState, err := r.next.RestoreRealState(ctx, id)
+	return realState, r.fail("RestoreRealState", err)
+}
+
+func (r *instrumentedRealStateRepository) PurgeRealStates(ctx context.Context, deletedBefore time.Time) (uint64, error) {
+	defer r.observe("PurgeRealStates", time.Now())
+	purged, err := r.next.PurgeRealStates(ctx, deletedBefore)
+	return purged, r.fail("PurgeRealStates", err)
+}
+
+func (r *instrumentedRealStateRepository) ListPricePoints(ctx context.Context, id uint64) ([]domain.PricePoint, error) {
+	defer r.observe("ListPricePoints", time.Now())
+	points, err := r.next.ListPricePoints(ctx, id)
+	return points, r.fail("ListPricePoints", err)
+}
+
+func (r *instrumentedRealStateRepository) observe(method string, start time.Time) {
+	r.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// fail counts err, if any, under its error code and returns it unchanged.
+func (r *instrumentedRealStateRepository) fail(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := customerrors.UnexpectedError
+	var e customerrors.Error
+	if errors.As(err, &e) {
+		code = e.ErrorCode
+	}
+
+	r.errors.WithLabelValues(method, string(code)).Inc()
+
+	return err
+}
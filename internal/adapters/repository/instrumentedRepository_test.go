@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/repository"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/repotest"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+)
+
+func TestInstrumentedRealStateRepository(t *testing.T) {
+	repotest.TestRealStateRepository(t, func(t *testing.T) ports.RealStateRepository {
+		return repository.NewInstrumentedRealStateRepository(repository.NewMemoryRealStateRepository(), prometheus.NewRegistry())
+	})
+}
+
+func TestInstrumentedRealStateRepositoryMetrics(t *testing.T) {
+	ctx := context.Background()
+	registry := prometheus.NewRegistry()
+	r := repository.NewInstrumentedRealStateRepository(repository.NewMemoryRealStateRepository(), registry)
+
+	_, err := r.CreateRealState(ctx, domain.RealState{Registration: 1})
+	assert.NoError(t, err)
+
+	_, err = r.CreateRealState(ctx, domain.RealState{Registration: 1})
+	assert.Error(t, err)
+
+	_, err = r.GetRealState(ctx, 9)
+	assert.Error(t, err)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(registry, "realstate_repository_duration_seconds"))
+
+	expected := `
+# HELP realstate_repository_errors_total Errors returned by real state repository calls, by method and error code.
+# TYPE realstate_repository_errors_total counter
+realstate_repository_errors_total{code="RESOURCE_CONFLICT",method="CreateRealState"} 1
+realstate_repository_errors_total{code="RESOURCE_NOT_FOUND",method="GetRealState"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "realstate_repository_errors_total"))
+}
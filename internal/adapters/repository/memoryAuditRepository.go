@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+)
+
+// memoryAuditRepository reads the audit trail the memory real state
+// repository keeps along with its real states.
+type memoryAuditRepository struct {
+	realStates *memoryRealStateRepository
+}
+
+func NewMemoryAuditRepository(realStates *memoryRealStateRepository) *memoryAuditRepository {
+	return &memoryAuditRepository{realStates: realStates}
+}
+
+// ListAudit returns the entries of a real state newest first, along with how
+// many there are in total.
+func (r *memoryAuditRepository) ListAudit(ctx context.Context, realStateId uint64, page ports.Page) ([]domain.AuditEntry, uint64, error) {
+	r.realStates.mu.RLock()
+	defer r.realStates.mu.RUnlock()
+
+	entries := r.realStates.audit
+
+	var matches []domain.AuditEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].RealStateId == realStateId {
+			matches = append(matches, entries[i])
+		}
+	}
+
+	total := uint64(len(matches))
+	start := min(page.Offset, total)
+	end := min(start+page.Limit, total)
+
+	return append(make([]domain.AuditEntry, 0, end-start), matches[start:end]...), total, nil
+}
@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
+)
+
+type memoryRealState struct {
+	realState domain.RealState
+	deletedAt *time.Time
+}
+
+// memoryRealStateRepository keeps real states in process memory, behaving
+// like the SQL repositories: ids are never reused, registrations stay unique
+// across deleted real states until they are purged, and every change is
+// audited under the same lock that applies it.
+type memoryRealStateRepository struct {
+	mu          sync.RWMutex
+	lastId      uint64
+	rows        map[uint64]*memoryRealState
+	prices      map[uint64][]domain.PricePoint
+	audit       []domain.AuditEntry
+	lastAuditId uint64
+}
+
+func NewMemoryRealStateRepository() *memoryRealStateRepository {
+	return &memoryRealStateRepository{
+		rows:   map[uint64]*memoryRealState{},
+		prices: map[uint64][]domain.PricePoint{},
+	}
+}
+
+func (r *memoryRealStateRepository) CreateRealState(ctx context.Context, realState domain.RealState) (domain.RealState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.registrationTaken(realState.Registration, 0) {
+		return domain.RealState{}, customerrors.Conflict
+	}
+
+	at, actor := now(), identity.Actor(ctx)
+	realState.CreatedAt, realState.UpdatedAt = at, at
+	realState.CreatedBy, realState.UpdatedBy = actor, actor
+
+	r.lastId++
+	realState.Id = r.lastId
+	realState.Version = 1
+
+	r.rows[realState.Id] = &memoryRealState{realState: realState}
+	r.prices[realState.Id] = []domain.PricePoint{{Price: realState.Price, At: at}}
+	r.record(ctx, domain.AuditCreate, realState.Id, at, nil, &realState)
+
+	return realState, nil
+}
+
+func (r *memoryRealStateRepository) GetRealState(ctx context.Context, id uint64) (domain.RealState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, ok := r.live(id)
+	if !ok {
+		return domain.RealState{}, customerrors.NotFound
+	}
+
+	return row.realState, nil
+}
+
+func (r *memoryRealStateRepository) ListRealStates(ctx context.Context, query ports.RealStateQuery) ([]domain.RealState, uint64, error) {
+	for _, s := range query.Sort {
+		if _, ok := sortColumns[s.Field]; !ok {
+			return nil, 0, customerrors.BadRequest
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []domain.RealState
+	for _, row := range r.rows {
+		if row.deletedAt == nil && matchesFilter(row.realState, query.Filter) {
+			matches = append(matches, row.realState)
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b domain.RealState) int {
+		for _, s := range query.Sort {
+			if c := compareField(a, b, s.Field); c != 0 {
+				return direction(c, s.Desc)
+			}
+		}
+
+		return compareField(a, b, ports.SortById)
+	})
+
+	total := uint64(len(matches))
+	start := min(query.Page.Offset, total)
+	end := min(start+query.Page.Limit, total)
+
+	return append(make([]domain.RealState, 0, end-start), matches[start:end]...), total, nil
+}
+
+func (r *memoryRealStateRepository) ListRealStatesAfter(ctx context.Context, query ports.KeysetQuery) ([]domain.RealState, error) {
+	field := query.Sort.Field
+	if field == "" {
+		field = ports.SortById
+	}
+
+	if _, ok := sortColumns[field]; !ok {
+		return nil, customerrors.BadRequest
+	}
+
+	// keyset orders by the sort field and then the id, both in the sort
+	// direction, as buildKeyset does.
+	keyset := func(a, b domain.RealState) int {
+		c := compareField(a, b, field)
+		if c == 0 {
+			c = compareField(a, b, ports.SortById)
+		}
+
+		return direction(c, query.Sort.Desc)
+	}
+
+	var after *domain.RealState
+	if query.After != nil {
+		cursor, err := cursorRealState(field, *query.After)
+		if err != nil {
+			return nil, err
+		}
+		after = &cursor
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []domain.RealState
+	for _, row := range r.rows {
+		if row.deletedAt == nil && (after == nil || keyset(row.realState, *after) > 0) {
+			matches = append(matches, row.realState)
+		}
+	}
+
+	slices.SortFunc(matches, keyset)
+
+	end := min(query.Limit, uint64(len(matches)))
+
+	return append(make([]domain.RealState, 0, end), matches[:end]...), nil
+}
+
+// UpdateRealState only writes when the stored version still equals
+// realState.Version; a zero version updates unconditionally.
+func (r *memoryRealStateRepository) UpdateRealState(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.live(id)
+	if !ok {
+		return domain.RealState{}, customerrors.NotFound
+	}
+
+	if realState.Version != 0 && realState.Version != row.realState.Version {
+		return domain.RealState{}, customerrors.PreconditionFailed
+	}
+
+	if r.registrationTaken(realState.Registration, id) {
+		return domain.RealState{}, customerrors.Conflict
+	}
+
+	at, before := now(), row.realState
+	if row.realState.Price.Cmp(realState.Price) != 0 {
+		r.prices[id] = append(r.prices[id], domain.PricePoint{Price: realState.Price, At: at})
+	}
+
+	stored := &row.realState
+	stored.Registration = realState.Registration
+	stored.Address = realState.Address
+	stored.Size = realState.Size
+	stored.Price = realState.Price
+	stored.State = realState.State
+	stored.UpdatedAt = at
+	stored.UpdatedBy = identity.Actor(ctx)
+	stored.Version++
+
+	r.record(ctx, domain.AuditUpdate, id, at, &before, stored)
+
+	return *stored, nil
+}
+
+func (r *memoryRealStateRepository) DeleteRealState(ctx context.Context, id uint64, version uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.live(id)
+	if !ok {
+		return customerrors.NotFound
+	}
+
+	if version != 0 && version != row.realState.Version {
+		return customerrors.PreconditionFailed
+	}
+
+	at, before := now(), row.realState
+	row.deletedAt = &at
+	row.realState.UpdatedAt = at
+	row.realState.UpdatedBy = identity.Actor(ctx)
+	row.realState.Version++
+
+	r.record(ctx, domain.AuditDelete, id, at, &before, nil)
+
+	return nil
+}
+
+func (r *memoryRealStateRepository) RestoreRealState(ctx context.Context, id uint64) (domain.RealState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.rows[id]
+	if !ok || row.deletedAt == nil {
+		return domain.RealState{}, customerrors.NotFound
+	}
+
+	at := now()
+	row.deletedAt = nil
+	row.realState.UpdatedAt = at
+	row.realState.UpdatedBy = identity.Actor(ctx)
+	row.realState.Version++
+
+	r.record(ctx, domain.AuditRestore, id, at, nil, &row.realState)
+
+	return row.realState, nil
+}
+
+func (r *memoryRealStateRepository) PurgeRealStates(ctx context.Context, deletedBefore time.Time) (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged uint64
+	for id, row := range r.rows {
+		if row.deletedAt != nil && row.deletedAt.Before(deletedBefore) {
+			delete(r.rows, id)
+			delete(r.prices, id)
+			purged++
+		}
+	}
+
+	r.audit = slices.DeleteFunc(r.audit, func(entry domain.AuditEntry) bool {
+		_, ok := r.rows[entry.RealStateId]
+		return !ok
+	})
+
+	return purged, nil
+}
+
+func (r *memoryRealStateRepository) ListPricePoints(ctx context.Context, id uint64) ([]domain.PricePoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.prices[id]), nil
+}
+
+func (r *memoryRealStateRepository) live(id uint64) (*memoryRealState, bool) {
+	row, ok := r.rows[id]
+	if !ok || row.deletedAt != nil {
+		return nil, false
+	}
+
+	return row, true
+}
+
+// record appends the change from before to after to the audit trail. The
+// caller holds the write lock.
+func (r *memoryRealStateRepository) record(ctx context.Context, operation domain.AuditOperation, id uint64, at time.Time, before, after *domain.RealState) {
+	r.lastAuditId++
+	r.audit = append(r.audit, domain.AuditEntry{
+		Id:          r.lastAuditId,
+		RealStateId: id,
+		Operation:   operation,
+		Actor:       identity.Actor(ctx),
+		At:          at,
+		Changes:     domain.Diff(before, after),
+	})
+}
+
+// registrationTaken reports whether a real state other than except, deleted
+// or not, holds registration.
+func (r *memoryRealStateRepository) registrationTaken(registration uint64, except uint64) bool {
+	for id, row := range r.rows {
+		if id != except && row.realState.Registration == registration {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesFilter mirrors buildWhere. The address match ignores case, like the
+// default collations of the SQL databases.
+func matchesFilter(realState domain.RealState, filter ports.RealStateFilter) bool {
+	switch {
+	case len(filter.States) > 0 && !slices.Contains(filter.States, realState.State):
+		return false
+	case filter.MinPrice != nil && realState.Price.Cmp(*filter.MinPrice) < 0:
+		return false
+	case filter.MaxPrice != nil && realState.Price.Cmp(*filter.MaxPrice) > 0:
+		return false
+	case filter.MinSize != nil && realState.Size < *filter.MinSize:
+		return false
+	case filter.MaxSize != nil && realState.Size > *filter.MaxSize:
+		return false
+	case filter.Registration != nil && realState.Registration != *filter.Registration:
+		return false
+	case filter.Address != "" && !strings.Contains(strings.ToLower(realState.Address), strings.ToLower(filter.Address)):
+		return false
+	default:
+		return true
+	}
+}
+
+func compareField(a, b domain.RealState, field ports.SortField) int {
+	switch field {
+	case ports.SortByRegistration:
+		return cmp.Compare(a.Registration, b.Registration)
+	case ports.SortByPrice:
+		return a.Price.Cmp(b.Price)
+	case ports.SortBySize:
+		return cmp.Compare(a.Size, b.Size)
+	case ports.SortByState:
+		return strings.Compare(a.State, b.State)
+	default:
+		return cmp.Compare(a.Id, b.Id)
+	}
+}
+
+func direction(c int, desc bool) int {
+	if desc {
+		return -c
+	}
+
+	return c
+}
+
+// cursorRealState holds the position of cursor in the fields keyset compares.
+func cursorRealState(field ports.SortField, cursor ports.Cursor) (domain.RealState, error) {
+	realState := domain.RealState{Id: cursor.LastId}
+	if field == ports.SortById {
+		return realState, nil
+	}
+
+	value, err := keysetValue(field, cursor.LastValue)
+	if err != nil {
+		return domain.RealState{}, err
+	}
+
+	switch field {
+	case ports.SortByPrice:
+		realState.Price = value.(domain.Money)
+	case ports.SortByRegistration:
+		realState.Registration = value.(uint64)
+	case ports.SortBySize:
+		realState.Size = value.(uint64)
+	case ports.SortByState:
+		realState.State = value.(string)
+	}
+
+	return realState, nil
+}
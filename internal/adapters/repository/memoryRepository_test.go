@@ -0,0 +1,63 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/repository"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/repotest"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+func TestMemoryRealStateRepository(t *testing.T) {
+	repotest.TestRealStateRepository(t, func(t *testing.T) ports.RealStateRepository {
+		return repository.NewMemoryRealStateRepository()
+	})
+}
+
+func TestMemoryAuditRepository(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) (ports.RealStateRepository, ports.AuditRepository) {
+		r := repository.NewMemoryRealStateRepository()
+		return r, repository.NewMemoryAuditRepository(r)
+	})
+}
+
+func TestMemoryRealStateRepositoryConcurrency(t *testing.T) {
+	ctx := context.Background()
+	r := repository.NewMemoryRealStateRepository()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		ids       = map[uint64]bool{}
+		conflicts int
+	)
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(registration uint64) {
+			defer wg.Done()
+
+			created, err := r.CreateRealState(ctx, domain.RealState{Registration: registration, Address: "123 Main St", Size: 100, State: "SP"})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				assert.Equal(t, customerrors.Conflict, err)
+				conflicts++
+				return
+			}
+			ids[created.Id] = true
+		}(uint64(i%25 + 1))
+	}
+
+	wg.Wait()
+
+	assert.Len(t, ids, 25, "every registration is created once, each with its own id")
+	assert.Equal(t, 25, conflicts)
+}
@@ -0,0 +1,513 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CreateMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT UNSIGNED NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME(6) NOT NULL)`
+	ListAppliedMigrations = `SELECT version, applied_at FROM schema_migrations ORDER BY version`
+	InsertMigration       = `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`
+	DeleteMigration       = `DELETE FROM schema_migrations WHERE version = ?`
+
+	CreateMigrationsTableSQLite = `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME NOT NULL)`
+
+	// PostgreSQL numbers its placeholders.
+	CreateMigrationsTablePostgres = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMPTZ NOT NULL)`
+	InsertMigrationPostgres       = `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`
+	DeleteMigrationPostgres       = `DELETE FROM schema_migrations WHERE version = $1`
+
+	// Session locks, held until released or the connection closes. Both wait
+	// for as long as another migrator holds them.
+	LockMySQL      = `SELECT GET_LOCK('schema_migrations', -1)`
+	UnlockMySQL    = `SELECT RELEASE_LOCK('schema_migrations')`
+	LockPostgres   = `SELECT pg_advisory_lock(hashtext('schema_migrations'))`
+	UnlockPostgres = `SELECT pg_advisory_unlock(hashtext('schema_migrations'))`
+)
+
+var (
+	ErrUnknownVersion = errors.New("unknown migration version")
+	ErrNotLocked      = errors.New("migration lock was not granted")
+)
+
+// Every database has its own scripts, with the same versions and names, so a
+// schema version means the same tables everywhere.
+//
+//go:embed mysql/*.sql sqlite/*.sql postgres/*.sql
+var scripts embed.FS
+
+var scriptName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Step is a migration run in one direction, or only recorded as applied
+// when it is a Baseline step.
+type Step struct {
+	Migration
+	Revert   bool
+	Baseline bool
+}
+
+func (s Step) String() string {
+	switch {
+	case s.Revert:
+		return "down " + s.Migration.String()
+	case s.Baseline:
+		return "baseline " + s.Migration.String()
+	default:
+		return "up " + s.Migration.String()
+	}
+}
+
+// MySQL returns the migrations of the MySQL schema embedded in the binary.
+func MySQL() ([]Migration, error) {
+	return embedded("mysql")
+}
+
+// SQLite returns the same migrations as MySQL, written for SQLite.
+func SQLite() ([]Migration, error) {
+	return embedded("sqlite")
+}
+
+// Postgres returns the same migrations as MySQL, written for PostgreSQL.
+func Postgres() ([]Migration, error) {
+	return embedded("postgres")
+}
+
+func embedded(dir string) ([]Migration, error) {
+	fsys, err := fs.Sub(scripts, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(fsys)
+}
+
+// Load reads migrations named NNNN_name.up.sql and NNNN_name.down.sql from
+// the root of fsys, ordered by version. Every version needs both scripts.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		match := scriptName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s: version must be a positive integer", entry.Name())
+		}
+
+		script, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d: named both %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, fmt.Errorf("migration %s: both up and down scripts are required", migration)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+type Migrator struct {
+	// Baseline is the version of a database created before the migrations
+	// existed, recognized by having BaselineTable but no applied migration.
+	// Moving such a database forward records the migrations up to Baseline
+	// as applied instead of running them.
+	Baseline      uint64
+	BaselineTable string
+
+	db         *sql.DB
+	migrations []Migration
+	create     string
+	insert     string
+	delete     string
+	// lock, when set, is taken on a connection of its own around every
+	// change, so replicas migrating on startup together run each migration
+	// once. unlock releases it.
+	lock   func(ctx context.Context, conn *sql.Conn) error
+	unlock string
+}
+
+// New returns a migrator for MySQL.
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		create:     CreateMigrationsTable,
+		insert:     InsertMigration,
+		delete:     DeleteMigration,
+		lock:       lockMySQL,
+		unlock:     UnlockMySQL,
+	}
+}
+
+// NewSQLite returns a migrator for SQLite, which needs no lock: its
+// databases are files of a single process.
+func NewSQLite(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		create:     CreateMigrationsTableSQLite,
+		insert:     InsertMigration,
+		delete:     DeleteMigration,
+	}
+}
+
+func NewPostgres(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		create:     CreateMigrationsTablePostgres,
+		insert:     InsertMigrationPostgres,
+		delete:     DeleteMigrationPostgres,
+		lock:       lockPostgres,
+		unlock:     UnlockPostgres,
+	}
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		at, ok := applied[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: at})
+	}
+
+	return statuses, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) ([]Step, error) {
+	if len(m.migrations) == 0 {
+		return nil, nil
+	}
+
+	return m.Goto(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down reverts the most recently applied migration, if any.
+func (m *Migrator) Down(ctx context.Context) ([]Step, error) {
+	return m.locked(ctx, func() ([]Step, error) {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		target := uint64(0)
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; !ok {
+				continue
+			}
+
+			if i > 0 {
+				target = m.migrations[i-1].Version
+			}
+
+			return m.migrate(ctx, applied, target)
+		}
+
+		return nil, nil
+	})
+}
+
+// Goto applies or reverts migrations until exactly those up to version are
+// applied. Version zero reverts all of them. MySQL commits DDL as it runs, so
+// a failing script leaves the earlier steps in place and returns them.
+func (m *Migrator) Goto(ctx context.Context, version uint64) ([]Step, error) {
+	if err := m.known(version); err != nil {
+		return nil, err
+	}
+
+	return m.locked(ctx, func() ([]Step, error) {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var steps []Step
+		if len(applied) == 0 && m.Baseline != 0 && version >= m.Baseline {
+			if steps, err = m.adopt(ctx, applied); err != nil {
+				return steps, err
+			}
+		}
+
+		more, err := m.migrate(ctx, applied, version)
+		return append(steps, more...), err
+	})
+}
+
+// MarkApplied records the migrations up to version as applied without running
+// them, for databases whose schema already has their changes.
+func (m *Migrator) MarkApplied(ctx context.Context, version uint64) ([]Step, error) {
+	if err := m.known(version); err != nil {
+		return nil, err
+	}
+
+	return m.locked(ctx, func() ([]Step, error) {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return m.mark(ctx, applied, version)
+	})
+}
+
+func (m *Migrator) known(version uint64) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == version }) {
+		return fmt.Errorf("%w %d", ErrUnknownVersion, version)
+	}
+
+	return nil
+}
+
+// adopt marks the database as being at Baseline when it already has
+// BaselineTable. Selecting from a missing table fails on every database,
+// which is how its absence is told apart.
+func (m *Migrator) adopt(ctx context.Context, applied map[uint64]time.Time) ([]Step, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT 1 FROM "+m.BaselineTable+" WHERE 1 = 0")
+	if err != nil {
+		return nil, nil
+	}
+	rows.Close()
+
+	return m.mark(ctx, applied, m.Baseline)
+}
+
+func (m *Migrator) mark(ctx context.Context, applied map[uint64]time.Time, version uint64) ([]Step, error) {
+	var steps []Step
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok || migration.Version > version {
+			continue
+		}
+
+		step := Step{Migration: migration, Baseline: true}
+		if err := m.run(ctx, step); err != nil {
+			return steps, err
+		}
+
+		applied[migration.Version] = time.Time{}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+func (m *Migrator) migrate(ctx context.Context, applied map[uint64]time.Time, version uint64) ([]Step, error) {
+	var steps []Step
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+			continue
+		}
+
+		step := Step{Migration: migration, Revert: true}
+		if err := m.run(ctx, step); err != nil {
+			return steps, err
+		}
+
+		steps = append(steps, step)
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok || migration.Version > version {
+			continue
+		}
+
+		step := Step{Migration: migration}
+		if err := m.run(ctx, step); err != nil {
+			return steps, err
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// locked runs fn holding the migration lock, if the database has one.
+func (m *Migrator) locked(ctx context.Context, fn func() ([]Step, error)) (steps []Step, err error) {
+	if m.lock == nil {
+		return fn()
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := m.lock(ctx, conn); err != nil {
+		return nil, fmt.Errorf("taking the migration lock: %w", err)
+	}
+
+	defer func() {
+		// The lock is released even when ctx was cancelled mid-way.
+		if _, unlockErr := conn.ExecContext(context.Background(), m.unlock); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("releasing the migration lock: %w", unlockErr))
+		}
+	}()
+
+	return fn()
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[uint64]time.Time, error) {
+	if _, err := m.db.ExecContext(ctx, m.create); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, ListAppliedMigrations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[uint64]time.Time{}
+	for rows.Next() {
+		var (
+			version uint64
+			at      time.Time
+		)
+
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+
+		applied[version] = at
+	}
+
+	return applied, rows.Err()
+}
+
+func (m *Migrator) run(ctx context.Context, step Step) error {
+	script := step.Up
+	switch {
+	case step.Revert:
+		script = step.Migration.Down
+	case step.Baseline:
+		script = ""
+	}
+
+	for _, statement := range statements(script) {
+		if _, err := m.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("%s: %w", step, err)
+		}
+	}
+
+	var err error
+	if step.Revert {
+		_, err = m.db.ExecContext(ctx, m.delete, step.Version)
+	} else {
+		_, err = m.db.ExecContext(ctx, m.insert, step.Version, step.Name, time.Now().UTC().Truncate(time.Microsecond))
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: recording: %w", step, err)
+	}
+
+	return nil
+}
+
+// statements splits a script on semicolons, so scripts must not contain one
+// inside a literal. Lines starting with "--" are dropped as comments.
+func statements(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var result []string
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			result = append(result, statement)
+		}
+	}
+
+	return result
+}
+
+// lockMySQL takes the lock GET_LOCK answers 1 for; 0 or NULL mean it was not
+// granted.
+func lockMySQL(ctx context.Context, conn *sql.Conn) error {
+	var granted sql.NullInt64
+	if err := conn.QueryRowContext(ctx, LockMySQL).Scan(&granted); err != nil {
+		return err
+	}
+
+	if granted.Int64 != 1 {
+		return ErrNotLocked
+	}
+
+	return nil
+}
+
+func lockPostgres(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, LockPostgres)
+	return err
+}
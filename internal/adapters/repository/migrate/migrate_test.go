@@ -0,0 +1,473 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/migrate"
+)
+
+var (
+	appliedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	migrations = []migrate.Migration{
+		{Version: 1, Name: "create_houses", Up: "CREATE TABLE houses (id INT);", Down: "DROP TABLE houses;"},
+		{Version: 2, Name: "create_rooms", Up: "-- rooms of a house\nCREATE TABLE rooms (id INT);\nCREATE INDEX rooms_id ON rooms (id);", Down: "DROP TABLE rooms;"},
+		{Version: 3, Name: "create_doors", Up: "CREATE TABLE doors (id INT);", Down: "DROP TABLE doors;"},
+	}
+)
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...uint64) {
+	mock.ExpectExec(regexp.QuoteMeta(migrate.CreateMigrationsTable)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, appliedAt)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(migrate.ListAppliedMigrations)).WillReturnRows(rows)
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(migrate.LockMySQL)).WillReturnRows(sqlmock.NewRows([]string{"granted"}).AddRow(1))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(migrate.UnlockMySQL)).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUp(mock sqlmock.Sqlmock, version uint64, name string, statements ...string) {
+	for _, statement := range statements {
+		mock.ExpectExec(regexp.QuoteMeta(statement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(migrate.InsertMigration)).
+		WithArgs(version, name, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func expectDown(mock sqlmock.Sqlmock, version uint64, statements ...string) {
+	for _, statement := range statements {
+		mock.ExpectExec(regexp.QuoteMeta(statement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(migrate.DeleteMigration)).
+		WithArgs(version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestLoad(t *testing.T) {
+	type output struct {
+		migrations []migrate.Migration
+		err        bool
+	}
+
+	testCases := []struct {
+		name       string
+		input      fstest.MapFS
+		expected   output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name: "When scripts are paired, should return the migrations ordered by version",
+			input: fstest.MapFS{
+				"0002_create_rooms.up.sql":    {Data: []byte("CREATE TABLE rooms (id INT);")},
+				"0002_create_rooms.down.sql":  {Data: []byte("DROP TABLE rooms;")},
+				"0001_create_houses.up.sql":   {Data: []byte("CREATE TABLE houses (id INT);")},
+				"0001_create_houses.down.sql": {Data: []byte("DROP TABLE houses;")},
+				"README.md":                   {Data: []byte("not a migration")},
+			},
+			expected: output{
+				migrations: []migrate.Migration{
+					{Version: 1, Name: "create_houses", Up: "CREATE TABLE houses (id INT);", Down: "DROP TABLE houses;"},
+					{Version: 2, Name: "create_rooms", Up: "CREATE TABLE rooms (id INT);", Down: "DROP TABLE rooms;"},
+				},
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When down script is missing, should return error",
+			input: fstest.MapFS{
+				"0001_create_houses.up.sql": {Data: []byte("CREATE TABLE houses (id INT);")},
+			},
+			expected: output{err: true},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When version has two names, should return error",
+			input: fstest.MapFS{
+				"0001_create_houses.up.sql":  {Data: []byte("CREATE TABLE houses (id INT);")},
+				"0001_create_homes.down.sql": {Data: []byte("DROP TABLE houses;")},
+			},
+			expected: output{err: true},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When version is zero, should return error",
+			input: fstest.MapFS{
+				"0000_create_houses.up.sql":   {Data: []byte("CREATE TABLE houses (id INT);")},
+				"0000_create_houses.down.sql": {Data: []byte("DROP TABLE houses;")},
+			},
+			expected: output{err: true},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			migrations, err := migrate.Load(tc.input)
+
+			tc.assertions(t, output{migrations: migrations, err: err != nil}, tc.expected)
+		})
+	}
+}
+
+func TestMySQL(t *testing.T) {
+	migrations, err := migrate.MySQL()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0001_create_real_states", migrations[0].String())
+	assert.Equal(t, "0006_add_real_state_deleted_at", migrations[len(migrations)-1].String())
+}
+
+func TestEmbeddedDialects(t *testing.T) {
+	mysql, err := migrate.MySQL()
+	assert.NoError(t, err)
+
+	for name, load := range map[string]func() ([]migrate.Migration, error){"sqlite": migrate.SQLite, "postgres": migrate.Postgres} {
+		migrations, err := load()
+		assert.NoError(t, err)
+		require.Len(t, migrations, len(mysql), "%s must have a script for every MySQL migration", name)
+
+		for i, migration := range migrations {
+			assert.Equal(t, mysql[i].String(), migration.String(), "%s must number and name its migrations as MySQL does", name)
+
+			for _, script := range []string{migration.Up, migration.Down} {
+				assert.NotRegexp(t, `AUTO_INCREMENT|UNSIGNED|DATETIME\(6\)|INT\(| ON real_states;`, script, "%s %s must not keep MySQL types", name, migration)
+			}
+		}
+	}
+}
+
+func TestGoto(t *testing.T) {
+	type output struct {
+		steps []string
+		err   error
+	}
+
+	testCases := []struct {
+		name       string
+		input      uint64
+		mocking    func(mock sqlmock.Sqlmock) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When target is ahead, should apply the pending migrations up to it in order",
+			input: 2,
+			mocking: func(mock sqlmock.Sqlmock) output {
+				expectLock(mock)
+				expectApplied(mock)
+				expectUp(mock, 1, "create_houses", "CREATE TABLE houses (id INT)")
+				expectUp(mock, 2, "create_rooms", "CREATE TABLE rooms (id INT)", "CREATE INDEX rooms_id ON rooms (id)")
+				expectUnlock(mock)
+
+				return output{steps: []string{"up 0001_create_houses", "up 0002_create_rooms"}}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When target is behind, should revert the migrations above it newest first",
+			input: 1,
+			mocking: func(mock sqlmock.Sqlmock) output {
+				expectLock(mock)
+				expectApplied(mock, 1, 2, 3)
+				expectDown(mock, 3, "DROP TABLE doors")
+				expectDown(mock, 2, "DROP TABLE rooms")
+				expectUnlock(mock)
+
+				return output{steps: []string{"down 0003_create_doors", "down 0002_create_rooms"}}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When target is zero, should revert every applied migration",
+			input: 0,
+			mocking: func(mock sqlmock.Sqlmock) output {
+				expectLock(mock)
+				expectApplied(mock, 1)
+				expectDown(mock, 1, "DROP TABLE houses")
+				expectUnlock(mock)
+
+				return output{steps: []string{"down 0001_create_houses"}}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When target is already reached, should do nothing",
+			input: 2,
+			mocking: func(mock sqlmock.Sqlmock) output {
+				expectLock(mock)
+				expectApplied(mock, 1, 2)
+				expectUnlock(mock)
+
+				return output{}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When target is unknown, should return error",
+			input: 9,
+			mocking: func(mock sqlmock.Sqlmock) output {
+				return output{err: migrate.ErrUnknownVersion}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.steps, actual.steps)
+				assert.ErrorIs(t, actual.err, expected.err)
+			},
+		},
+		{
+			name:  "When another migrator holds the lock and it is not granted, should change nothing",
+			input: 2,
+			mocking: func(mock sqlmock.Sqlmock) output {
+				mock.ExpectQuery(regexp.QuoteMeta(migrate.LockMySQL)).WillReturnRows(sqlmock.NewRows([]string{"granted"}).AddRow(0))
+
+				return output{err: migrate.ErrNotLocked}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.steps, actual.steps)
+				assert.ErrorIs(t, actual.err, expected.err)
+			},
+		},
+		{
+			name:  "When a script fails, should stop and return the steps already run",
+			input: 3,
+			mocking: func(mock sqlmock.Sqlmock) output {
+				failure := errors.New("syntax error")
+
+				expectLock(mock)
+				expectApplied(mock, 1)
+				expectUp(mock, 2, "create_rooms", "CREATE TABLE rooms (id INT)", "CREATE INDEX rooms_id ON rooms (id)")
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE doors (id INT)")).WillReturnError(failure)
+				expectUnlock(mock)
+
+				return output{steps: []string{"up 0002_create_rooms"}, err: failure}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected.steps, actual.steps)
+				assert.ErrorIs(t, actual.err, expected.err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock)
+
+			m := migrate.New(db, migrations)
+			steps, err := m.Goto(ctx, tc.input)
+
+			actual := output{err: err}
+			for _, step := range steps {
+				actual.steps = append(actual.steps, step.String())
+			}
+
+			tc.assertions(t, actual, expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	expectLock(mock)
+	expectApplied(mock, 1, 2)
+	expectDown(mock, 2, "DROP TABLE rooms")
+	expectUnlock(mock)
+
+	steps, err := migrate.New(db, migrations).Down(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []migrate.Step{{Migration: migrations[1], Revert: true}}, steps)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBaseline(t *testing.T) {
+	type output struct {
+		steps []string
+		err   error
+	}
+
+	testCases := []struct {
+		name       string
+		mocking    func(mock sqlmock.Sqlmock) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name: "When database predates the migrations, should record the baseline and apply the rest",
+			mocking: func(mock sqlmock.Sqlmock) output {
+				expectLock(mock)
+				expectApplied(mock)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM houses WHERE 1 = 0")).WillReturnRows(sqlmock.NewRows([]string{"1"}))
+				expectUp(mock, 1, "create_houses")
+				expectUp(mock, 2, "create_rooms", "CREATE TABLE rooms (id INT)", "CREATE INDEX rooms_id ON rooms (id)")
+				expectUnlock(mock)
+
+				return output{steps: []string{"baseline 0001_create_houses", "up 0002_create_rooms"}}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When database is empty, should apply every migration",
+			mocking: func(mock sqlmock.Sqlmock) output {
+				expectLock(mock)
+				expectApplied(mock)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM houses WHERE 1 = 0")).WillReturnError(errors.New("table houses doesn't exist"))
+				expectUp(mock, 1, "create_houses", "CREATE TABLE houses (id INT)")
+				expectUp(mock, 2, "create_rooms", "CREATE TABLE rooms (id INT)", "CREATE INDEX rooms_id ON rooms (id)")
+				expectUnlock(mock)
+
+				return output{steps: []string{"up 0001_create_houses", "up 0002_create_rooms"}}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When migrations were already applied, should not look for the baseline table",
+			mocking: func(mock sqlmock.Sqlmock) output {
+				expectLock(mock)
+				expectApplied(mock, 1)
+				expectUp(mock, 2, "create_rooms", "CREATE TABLE rooms (id INT)", "CREATE INDEX rooms_id ON rooms (id)")
+				expectUnlock(mock)
+
+				return output{steps: []string{"up 0002_create_rooms"}}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock)
+
+			m := migrate.New(db, migrations)
+			m.Baseline, m.BaselineTable = 1, "houses"
+			steps, err := m.Goto(context.Background(), 2)
+
+			actual := output{err: err}
+			for _, step := range steps {
+				actual.steps = append(actual.steps, step.String())
+			}
+
+			tc.assertions(t, actual, expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkApplied(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	expectLock(mock)
+	expectApplied(mock, 1)
+	expectUp(mock, 2, "create_rooms")
+	expectUnlock(mock)
+
+	steps, err := migrate.New(db, migrations).MarkApplied(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []migrate.Step{{Migration: migrations[1], Baseline: true}}, steps)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	expectApplied(mock, 1)
+
+	statuses, err := migrate.New(db, migrations).Status(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []migrate.Status{
+		{Migration: migrations[0], Applied: true, AppliedAt: appliedAt},
+		{Migration: migrations[1]},
+		{Migration: migrations[2]},
+	}, statuses)
+}
+
+func TestNewPostgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(migrate.LockPostgres)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(migrate.CreateMigrationsTablePostgres)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(migrate.ListAppliedMigrations)).WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE houses (id INT)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(migrate.InsertMigrationPostgres)).
+		WithArgs(1, "create_houses", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(migrate.UnlockPostgres)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = migrate.NewPostgres(db, migrations).Goto(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
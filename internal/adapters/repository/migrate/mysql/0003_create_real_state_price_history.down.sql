@@ -0,0 +1 @@
+DROP TABLE real_state_price_history;
@@ -0,0 +1,8 @@
+CREATE TABLE real_state_price_history (
+    real_state_price_history_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    real_state_id INT NOT NULL,
+    real_state_price_history_price DECIMAL(15,2) NOT NULL,
+    real_state_price_history_at DATETIME(6) NOT NULL
+);
+
+CREATE INDEX real_state_price_history_real_state ON real_state_price_history (real_state_id, real_state_price_history_id);
@@ -0,0 +1,4 @@
+ALTER TABLE real_states DROP COLUMN real_state_updated_by;
+ALTER TABLE real_states DROP COLUMN real_state_created_by;
+ALTER TABLE real_states DROP COLUMN real_state_updated_at;
+ALTER TABLE real_states DROP COLUMN real_state_created_at;
@@ -0,0 +1,6 @@
+-- Real states created before this migration have no known creation time or
+-- author, so they keep the epoch and the actor of unauthenticated writes.
+ALTER TABLE real_states ADD COLUMN real_state_created_at DATETIME(6) NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE real_states ADD COLUMN real_state_updated_at DATETIME(6) NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE real_states ADD COLUMN real_state_created_by VARCHAR(255) NOT NULL DEFAULT 'anonymous';
+ALTER TABLE real_states ADD COLUMN real_state_updated_by VARCHAR(255) NOT NULL DEFAULT 'anonymous';
@@ -0,0 +1 @@
+ALTER TABLE real_states DROP COLUMN real_state_version;
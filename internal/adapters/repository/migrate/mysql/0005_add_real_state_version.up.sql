@@ -0,0 +1,2 @@
+-- Every existing real state starts at the version a new one is created with.
+ALTER TABLE real_states ADD COLUMN real_state_version BIGINT UNSIGNED NOT NULL DEFAULT 1;
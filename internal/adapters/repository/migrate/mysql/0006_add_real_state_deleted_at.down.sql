@@ -0,0 +1,2 @@
+DROP INDEX real_states_deleted_at ON real_states;
+ALTER TABLE real_states DROP COLUMN real_state_deleted_at;
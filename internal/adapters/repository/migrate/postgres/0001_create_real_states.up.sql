@@ -0,0 +1,8 @@
+CREATE TABLE real_states (
+    real_state_id SERIAL PRIMARY KEY,
+    real_state_registration INTEGER UNIQUE NOT NULL,
+    real_state_address TEXT NOT NULL,
+    real_state_size NUMERIC(10,2) NOT NULL,
+    real_state_price NUMERIC(15,2) NOT NULL,
+    real_state_state VARCHAR(2) NOT NULL
+);
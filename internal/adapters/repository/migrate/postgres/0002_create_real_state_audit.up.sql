@@ -0,0 +1,11 @@
+-- JSONB is the JSON type PostgreSQL can compare and index.
+CREATE TABLE real_state_audit (
+    real_state_audit_id BIGSERIAL PRIMARY KEY,
+    real_state_id INTEGER NOT NULL,
+    real_state_audit_operation VARCHAR(16) NOT NULL,
+    real_state_audit_actor VARCHAR(255) NOT NULL,
+    real_state_audit_at TIMESTAMPTZ NOT NULL,
+    real_state_audit_changes JSONB NOT NULL
+);
+
+CREATE INDEX real_state_audit_real_state ON real_state_audit (real_state_id, real_state_audit_id);
@@ -0,0 +1,2 @@
+ALTER TABLE real_states ADD COLUMN real_state_deleted_at TIMESTAMPTZ NULL;
+CREATE INDEX real_states_deleted_at ON real_states (real_state_deleted_at);
@@ -0,0 +1 @@
+DROP TABLE real_states;
@@ -0,0 +1,10 @@
+-- SQLite gives DECIMAL columns float affinity, so the price is kept as
+-- INTEGER cents.
+CREATE TABLE real_states (
+    real_state_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    real_state_registration INTEGER UNIQUE NOT NULL,
+    real_state_address TEXT NOT NULL,
+    real_state_size DECIMAL(10,2) NOT NULL,
+    real_state_price INTEGER NOT NULL,
+    real_state_state VARCHAR(2) NOT NULL
+);
@@ -0,0 +1,10 @@
+CREATE TABLE real_state_audit (
+    real_state_audit_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    real_state_id INTEGER NOT NULL,
+    real_state_audit_operation VARCHAR(16) NOT NULL,
+    real_state_audit_actor VARCHAR(255) NOT NULL,
+    real_state_audit_at DATETIME NOT NULL,
+    real_state_audit_changes JSON NOT NULL
+);
+
+CREATE INDEX real_state_audit_real_state ON real_state_audit (real_state_id, real_state_audit_id);
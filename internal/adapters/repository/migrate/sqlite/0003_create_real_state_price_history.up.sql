@@ -0,0 +1,8 @@
+CREATE TABLE real_state_price_history (
+    real_state_price_history_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    real_state_id INTEGER NOT NULL,
+    real_state_price_history_price INTEGER NOT NULL,
+    real_state_price_history_at DATETIME NOT NULL
+);
+
+CREATE INDEX real_state_price_history_real_state ON real_state_price_history (real_state_id, real_state_price_history_id);
@@ -0,0 +1,67 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/repository"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/migrate"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/repotest"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+)
+
+// mysqlDSNEnv points the MySQL contract tests at a database they may wipe. The
+// DSN needs the options the server opens MySQL with, such as
+// user:pass@tcp(localhost:3306)/real_states_test?parseTime=true&clientFoundRows=true.
+// The test workflow in .github/workflows sets it to a MySQL service container.
+const mysqlDSNEnv = "MYSQL_TEST_DSN"
+
+// openMySQL returns a migrated database emptied after the test, skipping the
+// test when no database is configured.
+func openMySQL(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv(mysqlDSNEnv)
+	if dsn == "" {
+		t.Skip(mysqlDSNEnv + " is not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+
+	migrations, err := migrate.MySQL()
+	require.NoError(t, err)
+
+	_, err = migrate.New(db, migrations).Up(context.Background())
+	require.NoError(t, err)
+
+	// TRUNCATE resets AUTO_INCREMENT, which is fine since every case starts
+	// from an empty table.
+	t.Cleanup(func() {
+		for _, table := range []string{"real_states", "real_state_audit", "real_state_price_history"} {
+			_, err := db.Exec("TRUNCATE TABLE " + table)
+			assert.NoError(t, err)
+		}
+		db.Close()
+	})
+
+	return db
+}
+
+func TestMySQLRealStateRepository(t *testing.T) {
+	repotest.TestRealStateRepository(t, func(t *testing.T) ports.RealStateRepository {
+		return repository.NewRealStateRepository(openMySQL(t))
+	})
+}
+
+func TestMySQLAuditRepository(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) (ports.RealStateRepository, ports.AuditRepository) {
+		db := openMySQL(t)
+		return repository.NewRealStateRepository(db), repository.NewAuditRepository(db)
+	})
+}
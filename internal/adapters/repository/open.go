@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+var dbSystems = map[string]attribute.KeyValue{
+	"mysql":    semconv.DBSystemMySQL,
+	"postgres": semconv.DBSystemPostgreSQL,
+	"sqlite":   semconv.DBSystemSqlite,
+}
+
+// Open opens a database through driverName, tracing every query with the
+// global tracer provider. Spans carry the query in db.statement, never its
+// arguments.
+func Open(driverName, dsn string) (*sql.DB, error) {
+	options := []otelsql.Option{
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	}
+
+	if system, ok := dbSystems[driverName]; ok {
+		options = append(options, otelsql.WithAttributes(system))
+	}
+
+	return otelsql.Open(driverName, dsn, options...)
+}
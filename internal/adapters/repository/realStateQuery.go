@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+var sortColumns = map[ports.SortField]string{
+	ports.SortById:           "real_state_id",
+	ports.SortByRegistration: "real_state_registration",
+	ports.SortByPrice:        "real_state_price",
+	ports.SortBySize:         "real_state_size",
+	ports.SortByState:        "real_state_state",
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// buildWhere returns the conditions to append to the live rows filter of
+// ListRealStates and CountRealStates.
+func buildWhere(filter ports.RealStateFilter) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	if len(filter.States) > 0 {
+		placeholders := make([]string, len(filter.States))
+		for i, state := range filter.States {
+			placeholders[i] = "?"
+			args = append(args, state)
+		}
+
+		conditions = append(conditions, "real_state_state IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	if filter.MinPrice != nil {
+		conditions = append(conditions, "real_state_price >= ?")
+		args = append(args, *filter.MinPrice)
+	}
+
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, "real_state_price <= ?")
+		args = append(args, *filter.MaxPrice)
+	}
+
+	if filter.MinSize != nil {
+		conditions = append(conditions, "real_state_size >= ?")
+		args = append(args, *filter.MinSize)
+	}
+
+	if filter.MaxSize != nil {
+		conditions = append(conditions, "real_state_size <= ?")
+		args = append(args, *filter.MaxSize)
+	}
+
+	if filter.Registration != nil {
+		conditions = append(conditions, "real_state_registration = ?")
+		args = append(args, *filter.Registration)
+	}
+
+	if filter.Address != "" {
+		conditions = append(conditions, "real_state_address LIKE ? ESCAPE '!'")
+		args = append(args, "%"+likeEscaper.Replace(filter.Address)+"%")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " AND " + strings.Join(conditions, " AND "), args
+}
+
+// buildOrderBy only emits columns from sortColumns and always ends with the
+// primary key so that pages are stable between requests.
+func buildOrderBy(sort []ports.Sort) (string, error) {
+	var (
+		terms      []string
+		idIncluded bool
+	)
+
+	for _, s := range sort {
+		column, ok := sortColumns[s.Field]
+		if !ok {
+			return "", customerrors.BadRequest
+		}
+
+		if s.Field == ports.SortById {
+			idIncluded = true
+		}
+
+		if s.Desc {
+			column += " DESC"
+		}
+
+		terms = append(terms, column)
+	}
+
+	if !idIncluded {
+		terms = append(terms, sortColumns[ports.SortById])
+	}
+
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// buildKeyset seeks past the cursor instead of skipping rows with OFFSET. The
+// primary key breaks ties in the same direction as the sort key, so rows that
+// share a sort value are neither repeated nor skipped between pages.
+func buildKeyset(query ports.KeysetQuery) (string, []any, error) {
+	field := query.Sort.Field
+	if field == "" {
+		field = ports.SortById
+	}
+
+	column, ok := sortColumns[field]
+	if !ok {
+		return "", nil, customerrors.BadRequest
+	}
+
+	idColumn := sortColumns[ports.SortById]
+
+	op, direction := ">", ""
+	if query.Sort.Desc {
+		op, direction = "<", " DESC"
+	}
+
+	orderBy := " ORDER BY " + column + direction
+	if field != ports.SortById {
+		orderBy += ", " + idColumn + direction
+	}
+
+	if query.After == nil {
+		return orderBy, nil, nil
+	}
+
+	if field == ports.SortById {
+		return " AND " + idColumn + " " + op + " ?" + orderBy, []any{query.After.LastId}, nil
+	}
+
+	value, err := keysetValue(field, query.After.LastValue)
+	if err != nil {
+		return "", nil, err
+	}
+
+	where := " AND (" + column + " " + op + " ? OR (" + column + " = ? AND " + idColumn + " " + op + " ?))"
+
+	return where + orderBy, []any{value, value, query.After.LastId}, nil
+}
+
+func keysetValue(field ports.SortField, value string) (any, error) {
+	switch field {
+	case ports.SortByPrice:
+		v, err := domain.ParseMoney(value)
+		if err != nil {
+			return nil, customerrors.BadRequest
+		}
+
+		return v, nil
+	case ports.SortByRegistration, ports.SortBySize:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, customerrors.BadRequest
+		}
+
+		return v, nil
+	default:
+		return value, nil
+	}
+}
@@ -3,72 +3,467 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
 	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
 )
 
 const (
-	CreateRealState = `INSERT INTO real_states (real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state) VALUES (?, ?, ?, ?, ?);`
-	GetRealState    = `SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state FROM real_states WHERE real_state_id = ?`
-	UpdateRealState = `UPDATE real_states SET real_state_registration = ?, real_state_address = ?, real_state_size = ?, real_state_price = ?, real_state_state = ? WHERE real_state_id = ?`
-	DeleteRealState = `DELETE FROM real_states WHERE real_state_id = ?`
+	CreateRealState  = `INSERT INTO real_states (real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`
+	GetRealState     = `SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_id = ? AND real_state_deleted_at IS NULL`
+	ListRealStates   = `SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_deleted_at IS NULL`
+	CountRealStates  = `SELECT COUNT(*) FROM real_states WHERE real_state_deleted_at IS NULL`
+	UpdateRealState  = `UPDATE real_states SET real_state_registration = ?, real_state_address = ?, real_state_size = ?, real_state_price = ?, real_state_state = ?, real_state_updated_at = ?, real_state_updated_by = ?, real_state_version = real_state_version + 1 WHERE real_state_id = ? AND real_state_deleted_at IS NULL`
+	DeleteRealState  = `UPDATE real_states SET real_state_deleted_at = ?, real_state_updated_at = ?, real_state_updated_by = ?, real_state_version = real_state_version + 1 WHERE real_state_id = ? AND real_state_deleted_at IS NULL`
+	RestoreRealState = `UPDATE real_states SET real_state_deleted_at = NULL, real_state_updated_at = ?, real_state_updated_by = ?, real_state_version = real_state_version + 1 WHERE real_state_id = ? AND real_state_deleted_at IS NOT NULL`
+	PurgeRealStates  = `DELETE FROM real_states WHERE real_state_deleted_at < ?`
+
+	// Run before PurgeRealStates, in its transaction, so nothing is left
+	// behind that refers to the purged real states.
+	LockPurgedRealStates = `SELECT real_state_id FROM real_states WHERE real_state_deleted_at < ?`
+	PurgePricePoints     = `DELETE FROM real_state_price_history WHERE real_state_id IN (SELECT real_state_id FROM real_states WHERE real_state_deleted_at < ?)`
+	PurgeAuditEntries    = `DELETE FROM real_state_audit WHERE real_state_id IN (SELECT real_state_id FROM real_states WHERE real_state_deleted_at < ?)`
+
+	LockRealState        = `SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_id = ? AND real_state_deleted_at IS NULL`
+	LockDeletedRealState = `SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_id = ? AND real_state_deleted_at IS NOT NULL`
+	CreatePricePoint     = `INSERT INTO real_state_price_history (real_state_id, real_state_price_history_price, real_state_price_history_at) VALUES (?, ?, ?)`
+	ListPricePoints      = `SELECT real_state_price_history_price, real_state_price_history_at FROM real_state_price_history WHERE real_state_id = ? ORDER BY real_state_price_history_id`
+
+	// Appended to UpdateRealState and DeleteRealState for a compare-and-swap
+	// against the version the caller read.
+	MatchVersion = ` AND real_state_version = ?`
+
+	// Appended to CreateRealState where the driver has no LastInsertId.
+	ReturningRealStateId = ` RETURNING real_state_id`
 )
 
 type realStateRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect
 }
 
 func NewRealStateRepository(db *sql.DB) *realStateRepository {
 	return &realStateRepository{
-		db: db,
+		db:      db,
+		dialect: mysqlDialect,
+	}
+}
+
+// NewSQLiteRealStateRepository expects db to begin transactions with BEGIN
+// IMMEDIATE, as OpenSQLite does, which stands in for the row lock MySQL takes
+// while updating.
+func NewSQLiteRealStateRepository(db *sql.DB) *realStateRepository {
+	return &realStateRepository{
+		db:      db,
+		dialect: sqliteDialect,
+	}
+}
+
+func NewPostgresRealStateRepository(db *sql.DB) *realStateRepository {
+	return &realStateRepository{
+		db:      db,
+		dialect: postgresDialect,
 	}
 }
 
-func (r *realStateRepository) CreateRealState(ctx context.Context, realState domain.RealState) (int64, error) {
-	res, err := r.db.ExecContext(ctx, CreateRealState, realState.Registration, realState.Address, realState.Size, realState.Price, realState.State)
+// CreateRealState also records the listing price as the first point of the
+// price history, and the creation in the audit trail.
+func (r *realStateRepository) CreateRealState(ctx context.Context, realState domain.RealState) (domain.RealState, error) {
+	at, actor := now(), identity.Actor(ctx)
+	realState.CreatedAt, realState.UpdatedAt = at, at
+	realState.CreatedBy, realState.UpdatedBy = actor, actor
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.RealState{}, internalError(ctx, "CreateRealState", err)
+	}
+	defer tx.Rollback()
+
+	id, err := r.insertRealState(ctx, tx, realState)
 	if err != nil {
-		return -1, customerrors.Internal
+		if r.dialect.isDuplicateEntry(err) {
+			return domain.RealState{}, customerrors.Conflict
+		}
+
+		return domain.RealState{}, internalError(ctx, "CreateRealState", err)
+	}
+
+	realState.Id = uint64(id)
+	realState.Version = 1
+
+	if _, err := tx.ExecContext(ctx, r.dialect.bind(CreatePricePoint), id, r.dialect.price(realState.Price), at); err != nil {
+		return domain.RealState{}, internalError(ctx, "CreateRealState", err)
+	}
+
+	if err := r.recordAudit(ctx, tx, domain.AuditCreate, realState.Id, at, nil, &realState); err != nil {
+		return domain.RealState{}, internalError(ctx, "CreateRealState", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.RealState{}, internalError(ctx, "CreateRealState", err)
 	}
 
-	id, err := res.LastInsertId()
+	return realState, nil
+}
+
+func (r *realStateRepository) insertRealState(ctx context.Context, tx *sql.Tx, realState domain.RealState) (int64, error) {
+	args := []any{realState.Registration, realState.Address, realState.Size, r.dialect.price(realState.Price), realState.State, realState.CreatedAt, realState.UpdatedAt, realState.CreatedBy, realState.UpdatedBy}
+
+	var id int64
+	if r.dialect.returning {
+		err := tx.QueryRowContext(ctx, r.dialect.bind(CreateRealState+ReturningRealStateId), args...).Scan(&id)
+		return id, err
+	}
+
+	res, err := tx.ExecContext(ctx, CreateRealState, args...)
 	if err != nil {
-		return -1, customerrors.Internal
+		return 0, err
 	}
 
-	return id, nil
+	return res.LastInsertId()
 }
 
 func (r *realStateRepository) GetRealState(ctx context.Context, id uint64) (domain.RealState, error) {
 	var realState domain.RealState
 
-	row := r.db.QueryRowContext(ctx, GetRealState, id)
-	if err := row.Scan(&realState.Id, &realState.Registration, &realState.Address, &realState.Size, &realState.Price, &realState.State); err != nil {
+	row := r.db.QueryRowContext(ctx, r.dialect.bind(GetRealState), id)
+	if err := row.Scan(r.dialect.scanDest(&realState)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.RealState{}, customerrors.NotFound
 		}
 
-		return domain.RealState{}, customerrors.Internal
+		return domain.RealState{}, internalError(ctx, "GetRealState", err)
 	}
 
 	return realState, nil
 }
 
+func (r *realStateRepository) ListRealStates(ctx context.Context, query ports.RealStateQuery) ([]domain.RealState, uint64, error) {
+	var total uint64
+
+	orderBy, err := buildOrderBy(query.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where, args := buildWhere(query.Filter)
+	args = r.dialect.args(args)
+
+	if err := r.db.QueryRowContext(ctx, r.dialect.bind(CountRealStates+where), args...).Scan(&total); err != nil {
+		return nil, 0, internalError(ctx, "ListRealStates", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(ListRealStates+where+orderBy+" LIMIT ? OFFSET ?"), append(args, query.Page.Limit, query.Page.Offset)...)
+	if err != nil {
+		return nil, 0, internalError(ctx, "ListRealStates", err)
+	}
+	defer rows.Close()
+
+	realStates, err := r.scanRealStates(ctx, "ListRealStates", rows, query.Page.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return realStates, total, nil
+}
+
+func (r *realStateRepository) ListRealStatesAfter(ctx context.Context, query ports.KeysetQuery) ([]domain.RealState, error) {
+	keyset, args, err := buildKeyset(query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(ListRealStates+keyset+" LIMIT ?"), append(r.dialect.args(args), query.Limit)...)
+	if err != nil {
+		return nil, internalError(ctx, "ListRealStatesAfter", err)
+	}
+	defer rows.Close()
+
+	return r.scanRealStates(ctx, "ListRealStatesAfter", rows, query.Limit)
+}
+
+// UpdateRealState only writes when the stored version still equals
+// realState.Version; a zero version updates unconditionally. A new price is
+// appended to the price history, and the change to the audit trail, in the
+// same transaction.
 func (r *realStateRepository) UpdateRealState(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error) {
-	_, err := r.db.ExecContext(ctx, UpdateRealState, realState.Registration, realState.Address, realState.Size, realState.Price, realState.State, id)
+	at, actor := now(), identity.Actor(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return domain.RealState{}, customerrors.Internal
+		return domain.RealState{}, internalError(ctx, "UpdateRealState", err)
 	}
+	defer tx.Rollback()
+
+	// The lock keeps the previous values accurate until commit, so the price
+	// history and the audit trail see every change exactly once.
+	before, err := r.lockRealState(ctx, tx, LockRealState, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.RealState{}, customerrors.NotFound
+		}
+
+		return domain.RealState{}, internalError(ctx, "UpdateRealState", err)
+	}
+
+	query := UpdateRealState
+	args := []any{realState.Registration, realState.Address, realState.Size, r.dialect.price(realState.Price), realState.State, at, actor, id}
+	if realState.Version != 0 {
+		query += MatchVersion
+		args = append(args, realState.Version)
+	}
+
+	res, err := tx.ExecContext(ctx, r.dialect.bind(query), args...)
+	if err != nil {
+		if r.dialect.isDuplicateEntry(err) {
+			return domain.RealState{}, customerrors.Conflict
+		}
+
+		return domain.RealState{}, internalError(ctx, "UpdateRealState", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return domain.RealState{}, internalError(ctx, "UpdateRealState", err)
+	}
+
+	// The row is locked and exists, so only a version mismatch leaves it
+	// unmatched.
+	if affected == 0 {
+		return domain.RealState{}, customerrors.PreconditionFailed
+	}
+
+	if before.Price.Cmp(realState.Price) != 0 {
+		if _, err := tx.ExecContext(ctx, r.dialect.bind(CreatePricePoint), id, r.dialect.price(realState.Price), at); err != nil {
+			return domain.RealState{}, internalError(ctx, "UpdateRealState", err)
+		}
+	}
+
+	if err := r.recordAudit(ctx, tx, domain.AuditUpdate, id, at, &before, &realState); err != nil {
+		return domain.RealState{}, internalError(ctx, "UpdateRealState", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.RealState{}, internalError(ctx, "UpdateRealState", err)
+	}
+
+	// The creation metadata, which the caller never supplies, comes from the
+	// locked row, so the response needs no read outside the transaction.
+	realState.Id = id
+	realState.CreatedAt, realState.CreatedBy = before.CreatedAt, before.CreatedBy
+	realState.UpdatedAt, realState.UpdatedBy = at, actor
+	realState.Version = before.Version + 1
 
 	return realState, nil
 }
 
-func (r *realStateRepository) DeleteRealState(ctx context.Context, id uint64) error {
-	_, err := r.db.ExecContext(ctx, DeleteRealState, id)
+// DeleteRealState only marks the real state as deleted, which hides it from
+// every other read until it is restored or purged. Like UpdateRealState, it
+// only writes when the stored version still equals version, unless zero.
+func (r *realStateRepository) DeleteRealState(ctx context.Context, id uint64, version uint64) error {
+	at := now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return internalError(ctx, "DeleteRealState", err)
+	}
+	defer tx.Rollback()
+
+	before, err := r.lockRealState(ctx, tx, LockRealState, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customerrors.NotFound
+		}
+
+		return internalError(ctx, "DeleteRealState", err)
+	}
+
+	query := DeleteRealState
+	args := []any{at, at, identity.Actor(ctx), id}
+	if version != 0 {
+		query += MatchVersion
+		args = append(args, version)
+	}
+
+	res, err := tx.ExecContext(ctx, r.dialect.bind(query), args...)
 	if err != nil {
-		return customerrors.Internal
+		return internalError(ctx, "DeleteRealState", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return internalError(ctx, "DeleteRealState", err)
+	}
+
+	if affected == 0 {
+		return customerrors.PreconditionFailed
+	}
+
+	if err := r.recordAudit(ctx, tx, domain.AuditDelete, id, at, &before, nil); err != nil {
+		return internalError(ctx, "DeleteRealState", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return internalError(ctx, "DeleteRealState", err)
 	}
 
 	return nil
 }
+
+func (r *realStateRepository) RestoreRealState(ctx context.Context, id uint64) (domain.RealState, error) {
+	at, actor := now(), identity.Actor(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.RealState{}, internalError(ctx, "RestoreRealState", err)
+	}
+	defer tx.Rollback()
+
+	before, err := r.lockRealState(ctx, tx, LockDeletedRealState, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.RealState{}, customerrors.NotFound
+		}
+
+		return domain.RealState{}, internalError(ctx, "RestoreRealState", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, r.dialect.bind(RestoreRealState), at, actor, id); err != nil {
+		return domain.RealState{}, internalError(ctx, "RestoreRealState", err)
+	}
+
+	if err := r.recordAudit(ctx, tx, domain.AuditRestore, id, at, nil, &before); err != nil {
+		return domain.RealState{}, internalError(ctx, "RestoreRealState", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.RealState{}, internalError(ctx, "RestoreRealState", err)
+	}
+
+	restored := before
+	restored.UpdatedAt, restored.UpdatedBy = at, actor
+	restored.Version = before.Version + 1
+
+	return restored, nil
+}
+
+// PurgeRealStates removes for good the real states deleted before the given
+// time, along with their price history and audit trail, and reports how many
+// there were.
+func (r *realStateRepository) PurgeRealStates(ctx context.Context, deletedBefore time.Time) (uint64, error) {
+	deletedBefore = deletedBefore.UTC()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, internalError(ctx, "PurgeRealStates", err)
+	}
+	defer tx.Rollback()
+
+	// The lock keeps a real state from being restored once its history is
+	// gone.
+	rows, err := tx.QueryContext(ctx, r.dialect.bind(LockPurgedRealStates+r.dialect.forUpdate), deletedBefore)
+	if err != nil {
+		return 0, internalError(ctx, "PurgeRealStates", err)
+	}
+	rows.Close()
+
+	for _, query := range []string{PurgePricePoints, PurgeAuditEntries} {
+		if _, err := tx.ExecContext(ctx, r.dialect.bind(query), deletedBefore); err != nil {
+			return 0, internalError(ctx, "PurgeRealStates", err)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, r.dialect.bind(PurgeRealStates), deletedBefore)
+	if err != nil {
+		return 0, internalError(ctx, "PurgeRealStates", err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, internalError(ctx, "PurgeRealStates", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, internalError(ctx, "PurgeRealStates", err)
+	}
+
+	return uint64(purged), nil
+}
+
+// ListPricePoints returns the prices a real state has had, oldest first.
+func (r *realStateRepository) ListPricePoints(ctx context.Context, id uint64) ([]domain.PricePoint, error) {
+	rows, err := r.db.QueryContext(ctx, r.dialect.bind(ListPricePoints), id)
+	if err != nil {
+		return nil, internalError(ctx, "ListPricePoints", err)
+	}
+	defer rows.Close()
+
+	var points []domain.PricePoint
+	for rows.Next() {
+		var point domain.PricePoint
+
+		if err := rows.Scan(r.dialect.scanPrice(&point.Price), &point.At); err != nil {
+			return nil, internalError(ctx, "ListPricePoints", err)
+		}
+
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ctx, "ListPricePoints", err)
+	}
+
+	return points, nil
+}
+
+// lockRealState reads the real state query selects and locks its row until
+// the transaction ends.
+func (r *realStateRepository) lockRealState(ctx context.Context, tx *sql.Tx, query string, id uint64) (domain.RealState, error) {
+	var realState domain.RealState
+	err := tx.QueryRowContext(ctx, r.dialect.bind(query+r.dialect.forUpdate), id).Scan(r.dialect.scanDest(&realState)...)
+	return realState, err
+}
+
+// recordAudit appends the change from before to after to the audit trail
+// within the transaction making it, so neither is stored without the other.
+func (r *realStateRepository) recordAudit(ctx context.Context, tx *sql.Tx, operation domain.AuditOperation, id uint64, at time.Time, before, after *domain.RealState) error {
+	changes, err := json.Marshal(domain.Diff(before, after))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, r.dialect.bind(CreateAuditEntry), id, operation, identity.Actor(ctx), at, changes)
+	return err
+}
+
+// scanRealStates reads the rows of a real state list, reporting failures as
+// those of operation.
+func (r *realStateRepository) scanRealStates(ctx context.Context, operation string, rows *sql.Rows, capacity uint64) ([]domain.RealState, error) {
+	realStates := make([]domain.RealState, 0, capacity)
+	for rows.Next() {
+		var realState domain.RealState
+
+		if err := rows.Scan(r.dialect.scanDest(&realState)...); err != nil {
+			return nil, internalError(ctx, operation, err)
+		}
+
+		realStates = append(realStates, realState)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ctx, operation, err)
+	}
+
+	return realStates, nil
+}
+
+// now is truncated to the DATETIME(6) precision so the value returned on
+// create equals the one read back later.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -1,23 +1,55 @@
 package repository_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"log/slog"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/natanchagas/gin-crud/internal/adapters/repository"
 	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
 	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
+	"github.com/natanchagas/gin-crud/internal/pkg/logging"
 )
 
+var (
+	createdAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	updatedAt = time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)
+
+	realStateColumns = []string{
+		"real_state_id", "real_state_registration", "real_state_address", "real_state_size", "real_state_price", "real_state_state",
+		"real_state_created_at", "real_state_updated_at", "real_state_created_by", "real_state_updated_by", "real_state_version",
+	}
+)
+
+// storedRow is realState as the real state queries select it.
+func storedRow(realState domain.RealState) *sqlmock.Rows {
+	return sqlmock.NewRows(realStateColumns).
+		AddRow(realState.Id, realState.Registration, realState.Address, realState.Size, realState.Price.String(), realState.State, realState.CreatedAt, realState.UpdatedAt, realState.CreatedBy, realState.UpdatedBy, realState.Version)
+}
+
+// auditChanges is the changes column of the audit entry recording the change
+// from before to after.
+func auditChanges(before, after *domain.RealState) []byte {
+	changes, _ := json.Marshal(domain.Diff(before, after))
+	return changes
+}
+
 func TestCreateRealState(t *testing.T) {
 	type output struct {
-		id  int64
-		err error
+		realState domain.RealState
+		err       error
 	}
 
 	testCases := []struct {
@@ -32,22 +64,45 @@ func TestCreateRealState(t *testing.T) {
 				Registration: 987654321,
 				Address:      "456 Elm St",
 				Size:         200,
-				Price:        250000.50,
+				Price:        domain.NewMoney(25000050),
 				State:        "CA",
 			},
 			mocking: func(mock sqlmock.Sqlmock, realState domain.RealState) output {
+				mock.ExpectBegin()
 				mock.
 					ExpectExec("INSERT INTO real_states").
-					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State).
+					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State, sqlmock.AnyArg(), sqlmock.AnyArg(), "jane", "jane").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreatePricePoint)).
+					WithArgs(1, realState.Price, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WithArgs(1, domain.AuditCreate, "jane", sqlmock.AnyArg(), auditChanges(nil, &realState)).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
+				mock.ExpectCommit()
+
+				realState.Id = 1
+				realState.CreatedBy = "jane"
+				realState.UpdatedBy = "jane"
+				realState.Version = 1
+
 				return output{
-					id:  1,
-					err: nil,
+					realState: realState,
+					err:       nil,
 				}
 			},
 			assertions: func(t *testing.T, actual, expected output) {
-				assert.Equal(t, actual, expected)
+				assert.False(t, actual.realState.CreatedAt.IsZero())
+				assert.Equal(t, actual.realState.CreatedAt, actual.realState.UpdatedAt)
+
+				actual.realState.CreatedAt = time.Time{}
+				actual.realState.UpdatedAt = time.Time{}
+				assert.Equal(t, expected, actual)
 			},
 		},
 		{
@@ -56,18 +111,20 @@ func TestCreateRealState(t *testing.T) {
 				Registration: 987654321,
 				Address:      "456 Elm St",
 				Size:         200,
-				Price:        250000.50,
+				Price:        domain.NewMoney(25000050),
 				State:        "CA",
 			},
 			mocking: func(mock sqlmock.Sqlmock, realState domain.RealState) output {
+				mock.ExpectBegin()
 				mock.
 					ExpectExec("INSERT INTO real_states").
-					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State).
+					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State, sqlmock.AnyArg(), sqlmock.AnyArg(), "jane", "jane").
 					WillReturnResult(sqlmock.NewErrorResult(errors.New("unexpected error")))
+				mock.ExpectRollback()
 
 				return output{
-					id:  -1,
-					err: customerrors.Internal,
+					realState: domain.RealState{},
+					err:       customerrors.Internal,
 				}
 			},
 			assertions: func(t *testing.T, actual, expected output) {
@@ -80,18 +137,112 @@ func TestCreateRealState(t *testing.T) {
 				Registration: 987654321,
 				Address:      "456 Elm St",
 				Size:         200,
-				Price:        250000.50,
+				Price:        domain.NewMoney(25000050),
+				State:        "CA",
+			},
+			mocking: func(mock sqlmock.Sqlmock, realState domain.RealState) output {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec("INSERT INTO real_states").
+					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State, sqlmock.AnyArg(), sqlmock.AnyArg(), "jane", "jane").
+					WillReturnError(errors.New("insert error"))
+				mock.ExpectRollback()
+
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.Internal,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When listing price cannot be recorded, should roll back and return error",
+			input: domain.RealState{
+				Registration: 987654321,
+				Address:      "456 Elm St",
+				Size:         200,
+				Price:        domain.NewMoney(25000050),
+				State:        "CA",
+			},
+			mocking: func(mock sqlmock.Sqlmock, realState domain.RealState) output {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec("INSERT INTO real_states").
+					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State, sqlmock.AnyArg(), sqlmock.AnyArg(), "jane", "jane").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreatePricePoint)).
+					WithArgs(1, realState.Price, sqlmock.AnyArg()).
+					WillReturnError(errors.New("insert error"))
+				mock.ExpectRollback()
+
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.Internal,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When creation cannot be audited, should roll back and return error",
+			input: domain.RealState{
+				Registration: 987654321,
+				Address:      "456 Elm St",
+				Size:         200,
+				Price:        domain.NewMoney(25000050),
 				State:        "CA",
 			},
 			mocking: func(mock sqlmock.Sqlmock, realState domain.RealState) output {
+				mock.ExpectBegin()
 				mock.
 					ExpectExec("INSERT INTO real_states").
-					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State).
+					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State, sqlmock.AnyArg(), sqlmock.AnyArg(), "jane", "jane").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreatePricePoint)).
+					WithArgs(1, realState.Price, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
 					WillReturnError(errors.New("insert error"))
+				mock.ExpectRollback()
+
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.Internal,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When registration already exists, should return conflict",
+			input: domain.RealState{
+				Registration: 987654321,
+				Address:      "456 Elm St",
+				Size:         200,
+				Price:        domain.NewMoney(25000050),
+				State:        "CA",
+			},
+			mocking: func(mock sqlmock.Sqlmock, realState domain.RealState) output {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec("INSERT INTO real_states").
+					WithArgs(realState.Registration, realState.Address, realState.Size, realState.Price, realState.State, sqlmock.AnyArg(), sqlmock.AnyArg(), "jane", "jane").
+					WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '987654321' for key 'real_states.real_state_registration'"})
+				mock.ExpectRollback()
 
 				return output{
-					id:  -1,
-					err: customerrors.Internal,
+					realState: domain.RealState{},
+					err:       customerrors.Conflict,
 				}
 			},
 			assertions: func(t *testing.T, actual, expected output) {
@@ -102,7 +253,7 @@ func TestCreateRealState(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			ctx := context.Background()
+			ctx := identity.NewContext(context.Background(), identity.Caller{Id: "jane"})
 
 			db, mock, err := sqlmock.New()
 			if err != nil {
@@ -115,10 +266,10 @@ func TestCreateRealState(t *testing.T) {
 			r := repository.NewRealStateRepository(db)
 			var actual output
 
-			actual.id, actual.err = r.CreateRealState(ctx, tc.input)
-
-			tc.assertions(t, expected, actual)
+			actual.realState, actual.err = r.CreateRealState(ctx, tc.input)
 
+			tc.assertions(t, actual, expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -140,10 +291,41 @@ func TestGetRealState(t *testing.T) {
 			input: 1,
 			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
 				mock.
-					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state FROM real_states WHERE real_state_id = ?`).
+					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_id = ?`).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(realStateColumns).
+						AddRow(1, 987654321, "456 Elm St", 200, 250000.50, "CA", createdAt, updatedAt, "jane", "john", 3))
+
+				return output{
+					realState: domain.RealState{
+						Id:           1,
+						Registration: 987654321,
+						Address:      "456 Elm St",
+						Size:         200,
+						Price:        domain.NewMoney(25000050),
+						State:        "CA",
+						CreatedAt:    createdAt,
+						UpdatedAt:    updatedAt,
+						CreatedBy:    "jane",
+						UpdatedBy:    "john",
+						Version:      3,
+					},
+					err: nil,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When driver returns decimals as text, should read the size as a whole number",
+			input: 1,
+			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
+				mock.
+					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_id = ?`).
 					WithArgs(id).
-					WillReturnRows(sqlmock.NewRows([]string{"real_state_id", "real_state_registration", "real_state_address", "real_state_size", "real_state_price", "real_state_state"}).
-						AddRow(1, 987654321, "456 Elm St", 200, 250000.50, "CA"))
+					WillReturnRows(sqlmock.NewRows(realStateColumns).
+						AddRow(1, 987654321, "456 Elm St", []byte("200.00"), []byte("250000.50"), "CA", createdAt, updatedAt, "jane", "john", 3))
 
 				return output{
 					realState: domain.RealState{
@@ -151,8 +333,13 @@ func TestGetRealState(t *testing.T) {
 						Registration: 987654321,
 						Address:      "456 Elm St",
 						Size:         200,
-						Price:        250000.50,
+						Price:        domain.NewMoney(25000050),
 						State:        "CA",
+						CreatedAt:    createdAt,
+						UpdatedAt:    updatedAt,
+						CreatedBy:    "jane",
+						UpdatedBy:    "john",
+						Version:      3,
 					},
 					err: nil,
 				}
@@ -166,7 +353,7 @@ func TestGetRealState(t *testing.T) {
 			input: 1,
 			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
 				mock.
-					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state FROM real_states WHERE real_state_id = ?`).
+					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_id = ?`).
 					WithArgs(id).
 					WillReturnError(sql.ErrNoRows)
 
@@ -184,7 +371,7 @@ func TestGetRealState(t *testing.T) {
 			input: 1,
 			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
 				mock.
-					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state FROM real_states WHERE real_state_id = ?`).
+					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_id = ?`).
 					WithArgs(id).
 					WillReturnError(sql.ErrConnDone)
 
@@ -231,6 +418,41 @@ func TestUpdateRealState(t *testing.T) {
 		id        uint64
 	}
 
+	realState := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(27500000),
+		State:        "CA",
+	}
+
+	stored := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(25000000),
+		State:        "CA",
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+		CreatedBy:    "jane",
+		UpdatedBy:    "jane",
+		Version:      2,
+	}
+
+	unchanged := stored
+	unchanged.Price = realState.Price
+
+	// lock expects the row lock that reads the real state before the update.
+	lock := func(mock sqlmock.Sqlmock, id uint64, stored domain.RealState) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.LockRealState)).
+			WithArgs(id).
+			WillReturnRows(storedRow(stored))
+	}
+
 	testCases := []struct {
 		name       string
 		input      input
@@ -238,139 +460,1250 @@ func TestUpdateRealState(t *testing.T) {
 		assertions func(t *testing.T, actual, expected output)
 	}{
 		{
-			name: "When real state exists, should update real state",
-			input: input{
-				realState: domain.RealState{
-					Id:           1,
-					Registration: 987654321,
-					Address:      "456 Elm St",
-					Size:         200,
-					Price:        275000.00,
-					State:        "CA",
-				},
-				id: 1,
-			},
+			name:  "When real state exists, should update it, record the new price and the change, and return the stored metadata",
+			input: input{realState: realState, id: 1},
 			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				lock(mock, in.id, stored)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.UpdateRealState)).
+					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, sqlmock.AnyArg(), "john", in.id).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
 				mock.
-					ExpectExec(`UPDATE real_state`).
-					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, in.id).
+					ExpectExec(regexp.QuoteMeta(repository.CreatePricePoint)).
+					WithArgs(in.id, in.realState.Price, sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WithArgs(in.id, domain.AuditUpdate, "john", sqlmock.AnyArg(), auditChanges(&stored, &in.realState)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				updated := in.realState
+				updated.CreatedAt = createdAt
+				updated.CreatedBy = "jane"
+				updated.UpdatedBy = "john"
+				updated.Version = 3
+
 				return output{
-					realState: in.realState,
+					realState: updated,
 					err:       nil,
 				}
 			},
 			assertions: func(t *testing.T, actual, expected output) {
-				assert.Equal(t, actual, expected)
+				assert.True(t, actual.realState.UpdatedAt.After(createdAt))
+
+				actual.realState.UpdatedAt = time.Time{}
+				assert.Equal(t, expected, actual)
 			},
 		},
 		{
-			name: "When real state exists, but update fails, should return error",
-			input: input{
-				realState: domain.RealState{
-					Id:           1,
-					Registration: 987654321,
-					Address:      "456 Elm St",
-					Size:         200,
-					Price:        275000.00,
-					State:        "CA",
-				},
-				id: 1,
+			name:  "When price is unchanged, should update without recording a price",
+			input: input{realState: realState, id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				lock(mock, in.id, unchanged)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.UpdateRealState)).
+					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, sqlmock.AnyArg(), "john", in.id).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WithArgs(in.id, domain.AuditUpdate, "john", sqlmock.AnyArg(), []byte(`[]`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				updated := in.realState
+				updated.CreatedAt = createdAt
+				updated.CreatedBy = "jane"
+				updated.UpdatedBy = "john"
+				updated.Version = 3
+
+				return output{
+					realState: updated,
+					err:       nil,
+				}
 			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.True(t, actual.realState.UpdatedAt.After(createdAt))
+
+				actual.realState.UpdatedAt = time.Time{}
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When real state does not exist, should return not found",
+			input: input{realState: realState, id: 1},
 			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				mock.ExpectBegin()
 				mock.
-					ExpectExec(`UPDATE real_state`).
-					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, in.id).
-					WillReturnError(errors.New("update failed"))
+					ExpectQuery(regexp.QuoteMeta(repository.LockRealState)).
+					WithArgs(in.id).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
 
 				return output{
 					realState: domain.RealState{},
-					err:       customerrors.Internal,
+					err:       customerrors.NotFound,
 				}
 			},
 			assertions: func(t *testing.T, actual, expected output) {
 				assert.Equal(t, actual, expected)
 			},
 		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctx := context.Background()
-
-			db, mock, err := sqlmock.New()
-			if err != nil {
-				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-			}
-			defer db.Close()
-
-			expected := tc.mocking(mock, tc.input)
-
-			r := repository.NewRealStateRepository(db)
-			var actual output
-			actual.realState, actual.err = r.UpdateRealState(ctx, tc.input.realState, tc.input.id)
+		{
+			name:  "When rows affected cannot be read, should return error",
+			input: input{realState: realState, id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				lock(mock, in.id, stored)
 
-			tc.assertions(t, expected, actual)
-		})
-	}
-}
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.UpdateRealState)).
+					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, sqlmock.AnyArg(), "john", in.id).
+					WillReturnResult(sqlmock.NewErrorResult(errors.New("rows affected unavailable")))
+				mock.ExpectRollback()
 
-func TestDeleteRealState(t *testing.T) {
-	testCases := []struct {
-		name       string
-		input      uint64
-		mocking    func(mock sqlmock.Sqlmock, id uint64) error
-		assertions func(t *testing.T, actual, expected error)
-	}{
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.Internal,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
 		{
-			name:  "When real state exists, should delete real state",
-			input: 1,
-			mocking: func(mock sqlmock.Sqlmock, id uint64) error {
+			name:  "When real state exists, but update fails, should return error",
+			input: input{realState: realState, id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				lock(mock, in.id, stored)
+
 				mock.
-					ExpectExec(`DELETE FROM real_states`).
-					WithArgs(id).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+					ExpectExec(regexp.QuoteMeta(repository.UpdateRealState)).
+					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, sqlmock.AnyArg(), "john", in.id).
+					WillReturnError(errors.New("update failed"))
+				mock.ExpectRollback()
 
-				return nil
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.Internal,
+				}
 			},
-			assertions: func(t *testing.T, actual, expected error) {
+			assertions: func(t *testing.T, actual, expected output) {
 				assert.Equal(t, actual, expected)
 			},
 		},
 		{
-			name:  "When real state exists, but delete fails, should return error",
-			input: 1,
-			mocking: func(mock sqlmock.Sqlmock, id uint64) error {
+			name:  "When price cannot be recorded, should roll back and return error",
+			input: input{realState: realState, id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				lock(mock, in.id, stored)
+
 				mock.
-					ExpectExec(`DELETE FROM real_states`).
-					WithArgs(id).
-					WillReturnError(errors.New("delete failed"))
+					ExpectExec(regexp.QuoteMeta(repository.UpdateRealState)).
+					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, sqlmock.AnyArg(), "john", in.id).
+					WillReturnResult(sqlmock.NewResult(1, 1))
 
-				return customerrors.Internal
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreatePricePoint)).
+					WithArgs(in.id, in.realState.Price, sqlmock.AnyArg()).
+					WillReturnError(errors.New("insert failed"))
+				mock.ExpectRollback()
+
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.Internal,
+				}
 			},
-			assertions: func(t *testing.T, actual, expected error) {
+			assertions: func(t *testing.T, actual, expected output) {
 				assert.Equal(t, actual, expected)
 			},
 		},
-	}
+		{
+			name:  "When change cannot be audited, should roll back and return error",
+			input: input{realState: realState, id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				lock(mock, in.id, stored)
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctx := context.Background()
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.UpdateRealState)).
+					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, sqlmock.AnyArg(), "john", in.id).
+					WillReturnResult(sqlmock.NewResult(1, 1))
 
-			db, mock, err := sqlmock.New()
-			if err != nil {
-				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreatePricePoint)).
+					WithArgs(in.id, in.realState.Price, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WillReturnError(errors.New("insert failed"))
+				mock.ExpectRollback()
+
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.Internal,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When registration belongs to another real state, should return conflict",
+			input: input{realState: realState, id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				lock(mock, in.id, stored)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.UpdateRealState)).
+					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, sqlmock.AnyArg(), "john", in.id).
+					WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '987654321' for key 'real_states.real_state_registration'"})
+				mock.ExpectRollback()
+
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.Conflict,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When version is stale, should return precondition failed",
+			input: input{
+				realState: domain.RealState{
+					Registration: 987654321,
+					Address:      "456 Elm St",
+					Size:         200,
+					Price:        domain.NewMoney(27500000),
+					State:        "CA",
+					Version:      2,
+				},
+				id: 1,
+			},
+			mocking: func(mock sqlmock.Sqlmock, in input) output {
+				lock(mock, in.id, stored)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.UpdateRealState+repository.MatchVersion)).
+					WithArgs(in.realState.Registration, in.realState.Address, in.realState.Size, in.realState.Price, in.realState.State, sqlmock.AnyArg(), "john", in.id, in.realState.Version).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				return output{
+					realState: domain.RealState{},
+					err:       customerrors.PreconditionFailed,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := identity.NewContext(context.Background(), identity.Caller{Id: "john"})
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock, tc.input)
+
+			r := repository.NewRealStateRepository(db)
+			var actual output
+			actual.realState, actual.err = r.UpdateRealState(ctx, tc.input.realState, tc.input.id)
+
+			tc.assertions(t, actual, expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteRealState(t *testing.T) {
+	type input struct {
+		id      uint64
+		version uint64
+	}
+
+	stored := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(25000050),
+		State:        "SP",
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
+		CreatedBy:    "jane",
+		UpdatedBy:    "john",
+		Version:      3,
+	}
+
+	// lock expects the row lock that reads the real state before the delete.
+	lock := func(mock sqlmock.Sqlmock, id uint64) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.LockRealState)).
+			WithArgs(id).
+			WillReturnRows(storedRow(stored))
+	}
+
+	testCases := []struct {
+		name       string
+		input      input
+		mocking    func(mock sqlmock.Sqlmock, in input) error
+		assertions func(t *testing.T, actual, expected error)
+	}{
+		{
+			name:  "When real state exists, should mark it as deleted by the caller and record the change",
+			input: input{id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) error {
+				lock(mock, in.id)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.DeleteRealState)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "john", in.id).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WithArgs(in.id, domain.AuditDelete, "john", sqlmock.AnyArg(), auditChanges(&stored, nil)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				return nil
+			},
+			assertions: func(t *testing.T, actual, expected error) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When real state does not exist or is already deleted, should return not found",
+			input: input{id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) error {
+				mock.ExpectBegin()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.LockRealState)).
+					WithArgs(in.id).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				return customerrors.NotFound
+			},
+			assertions: func(t *testing.T, actual, expected error) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When real state exists, but delete fails, should return error",
+			input: input{id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) error {
+				lock(mock, in.id)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.DeleteRealState)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "john", in.id).
+					WillReturnError(errors.New("delete failed"))
+				mock.ExpectRollback()
+
+				return customerrors.Internal
+			},
+			assertions: func(t *testing.T, actual, expected error) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When deletion cannot be audited, should roll back and return error",
+			input: input{id: 1},
+			mocking: func(mock sqlmock.Sqlmock, in input) error {
+				lock(mock, in.id)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.DeleteRealState)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "john", in.id).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WillReturnError(errors.New("insert failed"))
+				mock.ExpectRollback()
+
+				return customerrors.Internal
+			},
+			assertions: func(t *testing.T, actual, expected error) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When version matches, should delete real state",
+			input: input{id: 1, version: 3},
+			mocking: func(mock sqlmock.Sqlmock, in input) error {
+				lock(mock, in.id)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.DeleteRealState+repository.MatchVersion)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "john", in.id, in.version).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WithArgs(in.id, domain.AuditDelete, "john", sqlmock.AnyArg(), auditChanges(&stored, nil)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				return nil
+			},
+			assertions: func(t *testing.T, actual, expected error) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When version is stale, should return precondition failed",
+			input: input{id: 1, version: 2},
+			mocking: func(mock sqlmock.Sqlmock, in input) error {
+				lock(mock, in.id)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.DeleteRealState+repository.MatchVersion)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "john", in.id, in.version).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				return customerrors.PreconditionFailed
+			},
+			assertions: func(t *testing.T, actual, expected error) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := identity.NewContext(context.Background(), identity.Caller{Id: "john"})
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock, tc.input)
+
+			r := repository.NewRealStateRepository(db)
+			actual := r.DeleteRealState(ctx, tc.input.id, tc.input.version)
+
+			tc.assertions(t, expected, actual)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRestoreRealState(t *testing.T) {
+	type output struct {
+		realState domain.RealState
+		err       error
+	}
+
+	deleted := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(25000050),
+		State:        "SP",
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+		CreatedBy:    "jane",
+		UpdatedBy:    "jane",
+		Version:      4,
+	}
+
+	// lock expects the row lock that reads the deleted real state.
+	lock := func(mock sqlmock.Sqlmock, id uint64) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.LockDeletedRealState)).
+			WithArgs(id).
+			WillReturnRows(storedRow(deleted))
+	}
+
+	testCases := []struct {
+		name       string
+		input      uint64
+		mocking    func(mock sqlmock.Sqlmock, id uint64) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When real state was deleted, should clear the mark, record the change and return it",
+			input: 1,
+			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
+				lock(mock, id)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.RestoreRealState)).
+					WithArgs(sqlmock.AnyArg(), "john", id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WithArgs(id, domain.AuditRestore, "john", sqlmock.AnyArg(), auditChanges(nil, &deleted)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+
+				return output{
+					realState: domain.RealState{
+						Id:           1,
+						Registration: 987654321,
+						Address:      "456 Elm St",
+						Size:         200,
+						Price:        domain.NewMoney(25000050),
+						State:        "SP",
+						CreatedAt:    createdAt,
+						CreatedBy:    "jane",
+						UpdatedBy:    "john",
+						Version:      5,
+					},
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.True(t, actual.realState.UpdatedAt.After(createdAt))
+
+				actual.realState.UpdatedAt = time.Time{}
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When real state does not exist or is not deleted, should return not found",
+			input: 1,
+			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
+				mock.ExpectBegin()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.LockDeletedRealState)).
+					WithArgs(id).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+
+				return output{err: customerrors.NotFound}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When restore fails, should return error",
+			input: 1,
+			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
+				lock(mock, id)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.RestoreRealState)).
+					WithArgs(sqlmock.AnyArg(), "john", id).
+					WillReturnError(errors.New("restore failed"))
+				mock.ExpectRollback()
+
+				return output{err: customerrors.Internal}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When restoration cannot be audited, should roll back and return error",
+			input: 1,
+			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
+				lock(mock, id)
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.RestoreRealState)).
+					WithArgs(sqlmock.AnyArg(), "john", id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.CreateAuditEntry)).
+					WillReturnError(errors.New("insert failed"))
+				mock.ExpectRollback()
+
+				return output{err: customerrors.Internal}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := identity.NewContext(context.Background(), identity.Caller{Id: "john"})
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock, tc.input)
+
+			r := repository.NewRealStateRepository(db)
+			var actual output
+			actual.realState, actual.err = r.RestoreRealState(ctx, tc.input)
+
+			tc.assertions(t, actual, expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPurgeRealStates(t *testing.T) {
+	type output struct {
+		purged uint64
+		err    error
+	}
+
+	deletedBefore := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// purgeChildren expects the lock on the real states to purge and the
+	// removal of their price history and audit trail.
+	purgeChildren := func(mock sqlmock.Sqlmock, deletedBefore time.Time) {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta(repository.LockPurgedRealStates)).
+			WithArgs(deletedBefore).
+			WillReturnRows(sqlmock.NewRows([]string{"real_state_id"}).AddRow(1).AddRow(2))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.PurgePricePoints)).
+			WithArgs(deletedBefore).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.
+			ExpectExec(regexp.QuoteMeta(repository.PurgeAuditEntries)).
+			WithArgs(deletedBefore).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+	}
+
+	testCases := []struct {
+		name       string
+		input      time.Time
+		mocking    func(mock sqlmock.Sqlmock, deletedBefore time.Time) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When deleted real states are old enough, should remove them with their history and return how many",
+			input: deletedBefore,
+			mocking: func(mock sqlmock.Sqlmock, deletedBefore time.Time) output {
+				purgeChildren(mock, deletedBefore)
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.PurgeRealStates)).
+					WithArgs(deletedBefore).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+
+				return output{purged: 2}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When purge fails, should return error",
+			input: deletedBefore,
+			mocking: func(mock sqlmock.Sqlmock, deletedBefore time.Time) output {
+				purgeChildren(mock, deletedBefore)
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.PurgeRealStates)).
+					WithArgs(deletedBefore).
+					WillReturnError(errors.New("purge failed"))
+				mock.ExpectRollback()
+
+				return output{err: customerrors.Internal}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When history cannot be removed, should roll back and return error",
+			input: deletedBefore,
+			mocking: func(mock sqlmock.Sqlmock, deletedBefore time.Time) output {
+				mock.ExpectBegin()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.LockPurgedRealStates)).
+					WithArgs(deletedBefore).
+					WillReturnRows(sqlmock.NewRows([]string{"real_state_id"}).AddRow(1))
+				mock.
+					ExpectExec(regexp.QuoteMeta(repository.PurgePricePoints)).
+					WithArgs(deletedBefore).
+					WillReturnError(errors.New("delete failed"))
+				mock.ExpectRollback()
+
+				return output{err: customerrors.Internal}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock, tc.input)
+
+			r := repository.NewRealStateRepository(db)
+			var actual output
+			actual.purged, actual.err = r.PurgeRealStates(ctx, tc.input)
+
+			tc.assertions(t, actual, expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListPricePoints(t *testing.T) {
+	type output struct {
+		points []domain.PricePoint
+		err    error
+	}
+
+	testCases := []struct {
+		name       string
+		input      uint64
+		mocking    func(mock sqlmock.Sqlmock, id uint64) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When prices were recorded, should return them oldest first",
+			input: 1,
+			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.ListPricePoints)).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"real_state_price_history_price", "real_state_price_history_at"}).
+						AddRow(250000.00, createdAt).
+						AddRow(260000.50, updatedAt))
+
+				return output{
+					points: []domain.PricePoint{
+						{Price: domain.NewMoney(25000000), At: createdAt},
+						{Price: domain.NewMoney(26000050), At: updatedAt},
+					},
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When select fails, should return error",
+			input: 1,
+			mocking: func(mock sqlmock.Sqlmock, id uint64) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.ListPricePoints)).
+					WithArgs(id).
+					WillReturnError(errors.New("select failed"))
+
+				return output{err: customerrors.Internal}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock, tc.input)
+
+			r := repository.NewRealStateRepository(db)
+			var actual output
+			actual.points, actual.err = r.ListPricePoints(ctx, tc.input)
+
+			tc.assertions(t, actual, expected)
+		})
+	}
+}
+
+func TestListRealStates(t *testing.T) {
+	type output struct {
+		realStates []domain.RealState
+		total      uint64
+		err        error
+	}
+
+	testCases := []struct {
+		name       string
+		input      ports.RealStateQuery
+		mocking    func(mock sqlmock.Sqlmock, query ports.RealStateQuery) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When real states exist, should return the requested page and total",
+			input: ports.RealStateQuery{Page: ports.Page{Limit: 2, Offset: 0}},
+			mocking: func(mock sqlmock.Sqlmock, query ports.RealStateQuery) output {
+				mock.
+					ExpectQuery(`SELECT COUNT\(\*\) FROM real_states WHERE real_state_deleted_at IS NULL`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				mock.
+					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_deleted_at IS NULL ORDER BY real_state_id LIMIT \? OFFSET \?`).
+					WithArgs(query.Page.Limit, query.Page.Offset).
+					WillReturnRows(sqlmock.NewRows(realStateColumns).
+						AddRow(1, 987654321, "456 Elm St", 200, 250000.50, "CA", createdAt, updatedAt, "jane", "john", 3).
+						AddRow(2, 123456789, "123 Oak St", 100, 150000.00, "SP", createdAt, updatedAt, "jane", "john", 3))
+
+				return output{
+					realStates: []domain.RealState{
+						{
+							Id:           1,
+							Registration: 987654321,
+							Address:      "456 Elm St",
+							Size:         200,
+							Price:        domain.NewMoney(25000050),
+							State:        "CA",
+							CreatedAt:    createdAt,
+							UpdatedAt:    updatedAt,
+							CreatedBy:    "jane",
+							UpdatedBy:    "john",
+							Version:      3,
+						},
+						{
+							Id:           2,
+							Registration: 123456789,
+							Address:      "123 Oak St",
+							Size:         100,
+							Price:        domain.NewMoney(15000000),
+							State:        "SP",
+							CreatedAt:    createdAt,
+							UpdatedAt:    updatedAt,
+							CreatedBy:    "jane",
+							UpdatedBy:    "john",
+							Version:      3,
+						},
+					},
+					total: 3,
+					err:   nil,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When there are no real states, should return an empty page",
+			input: ports.RealStateQuery{Page: ports.Page{Limit: 20, Offset: 0}},
+			mocking: func(mock sqlmock.Sqlmock, query ports.RealStateQuery) output {
+				mock.
+					ExpectQuery(`SELECT COUNT\(\*\) FROM real_states WHERE real_state_deleted_at IS NULL`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+				mock.
+					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_deleted_at IS NULL ORDER BY real_state_id LIMIT \? OFFSET \?`).
+					WithArgs(query.Page.Limit, query.Page.Offset).
+					WillReturnRows(sqlmock.NewRows(realStateColumns))
+
+				return output{
+					realStates: []domain.RealState{},
+					total:      0,
+					err:        nil,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When filters and sort are given, should bind them as parameters and order by whitelisted columns",
+			input: ports.RealStateQuery{
+				Filter: ports.RealStateFilter{
+					States:   []string{"SP", "RJ"},
+					MinPrice: func() *domain.Money { p := domain.NewMoney(10000000); return &p }(),
+					MaxSize:  func() *uint64 { s := uint64(300); return &s }(),
+					Address:  "50%_off",
+				},
+				Sort: []ports.Sort{
+					{Field: ports.SortByPrice},
+					{Field: ports.SortBySize, Desc: true},
+				},
+				Page: ports.Page{Limit: 10, Offset: 10},
+			},
+			mocking: func(mock sqlmock.Sqlmock, query ports.RealStateQuery) output {
+				where := ` AND real_state_state IN (?, ?) AND real_state_price >= ? AND real_state_size <= ? AND real_state_address LIKE ? ESCAPE '!'`
+
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.CountRealStates+where)).
+					WithArgs("SP", "RJ", domain.NewMoney(10000000), uint64(300), "%50!%!_off%").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.ListRealStates+where+` ORDER BY real_state_price, real_state_size DESC, real_state_id LIMIT ? OFFSET ?`)).
+					WithArgs("SP", "RJ", domain.NewMoney(10000000), uint64(300), "%50!%!_off%", query.Page.Limit, query.Page.Offset).
+					WillReturnRows(sqlmock.NewRows(realStateColumns).
+						AddRow(7, 123456789, "Av. Paulista 50%_off", 250, 180000.00, "SP", createdAt, updatedAt, "jane", "john", 3))
+
+				return output{
+					realStates: []domain.RealState{
+						{
+							Id:           7,
+							Registration: 123456789,
+							Address:      "Av. Paulista 50%_off",
+							Size:         250,
+							Price:        domain.NewMoney(18000000),
+							State:        "SP",
+							CreatedAt:    createdAt,
+							UpdatedAt:    updatedAt,
+							CreatedBy:    "jane",
+							UpdatedBy:    "john",
+							Version:      3,
+						},
+					},
+					total: 11,
+					err:   nil,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When sort field is not whitelisted, should return error without querying",
+			input: ports.RealStateQuery{
+				Sort: []ports.Sort{{Field: ports.SortField("real_state_price; DROP TABLE real_states")}},
+				Page: ports.Page{Limit: 20, Offset: 0},
+			},
+			mocking: func(mock sqlmock.Sqlmock, query ports.RealStateQuery) output {
+				return output{
+					realStates: nil,
+					total:      0,
+					err:        customerrors.BadRequest,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When count fails, should return error",
+			input: ports.RealStateQuery{Page: ports.Page{Limit: 20, Offset: 0}},
+			mocking: func(mock sqlmock.Sqlmock, query ports.RealStateQuery) output {
+				mock.
+					ExpectQuery(`SELECT COUNT\(\*\) FROM real_states WHERE real_state_deleted_at IS NULL`).
+					WillReturnError(sql.ErrConnDone)
+
+				return output{
+					realStates: nil,
+					total:      0,
+					err:        customerrors.Internal,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When select fails, should return error",
+			input: ports.RealStateQuery{Page: ports.Page{Limit: 20, Offset: 0}},
+			mocking: func(mock sqlmock.Sqlmock, query ports.RealStateQuery) output {
+				mock.
+					ExpectQuery(`SELECT COUNT\(\*\) FROM real_states WHERE real_state_deleted_at IS NULL`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				mock.
+					ExpectQuery(`SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_deleted_at IS NULL ORDER BY real_state_id LIMIT \? OFFSET \?`).
+					WithArgs(query.Page.Limit, query.Page.Offset).
+					WillReturnError(sql.ErrConnDone)
+
+				return output{
+					realStates: nil,
+					total:      0,
+					err:        customerrors.Internal,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expected := tc.mocking(mock, tc.input)
+
+			r := repository.NewRealStateRepository(db)
+			var actual output
+			actual.realStates, actual.total, actual.err = r.ListRealStates(ctx, tc.input)
+
+			tc.assertions(t, expected, actual)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListRealStatesAfter(t *testing.T) {
+	type output struct {
+		realStates []domain.RealState
+		err        error
+	}
+
+	selectRealStates := `SELECT real_state_id, real_state_registration, real_state_address, real_state_size, real_state_price, real_state_state, real_state_created_at, real_state_updated_at, real_state_created_by, real_state_updated_by, real_state_version FROM real_states WHERE real_state_deleted_at IS NULL`
+
+	testCases := []struct {
+		name       string
+		input      ports.KeysetQuery
+		mocking    func(mock sqlmock.Sqlmock, query ports.KeysetQuery) output
+		assertions func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When there is no cursor, should return the first rows ordered by id",
+			input: ports.KeysetQuery{Sort: ports.Sort{Field: ports.SortById}, Limit: 3},
+			mocking: func(mock sqlmock.Sqlmock, query ports.KeysetQuery) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(selectRealStates + ` ORDER BY real_state_id LIMIT ?`)).
+					WithArgs(query.Limit).
+					WillReturnRows(sqlmock.NewRows(realStateColumns).
+						AddRow(1, 987654321, "456 Elm St", 200, 250000.50, "SP", createdAt, updatedAt, "jane", "john", 3))
+
+				return output{
+					realStates: []domain.RealState{
+						{
+							Id:           1,
+							Registration: 987654321,
+							Address:      "456 Elm St",
+							Size:         200,
+							Price:        domain.NewMoney(25000050),
+							State:        "SP",
+							CreatedAt:    createdAt,
+							UpdatedAt:    updatedAt,
+							CreatedBy:    "jane",
+							UpdatedBy:    "john",
+							Version:      3,
+						},
+					},
+					err: nil,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When cursor is by id, should seek past the last id",
+			input: ports.KeysetQuery{
+				Sort:  ports.Sort{Field: ports.SortById},
+				After: &ports.Cursor{Sort: ports.Sort{Field: ports.SortById}, LastId: 10},
+				Limit: 3,
+			},
+			mocking: func(mock sqlmock.Sqlmock, query ports.KeysetQuery) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(selectRealStates+` AND real_state_id > ? ORDER BY real_state_id LIMIT ?`)).
+					WithArgs(uint64(10), query.Limit).
+					WillReturnRows(sqlmock.NewRows(realStateColumns))
+
+				return output{
+					realStates: []domain.RealState{},
+					err:        nil,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When cursor is by descending price, should seek past the last price with id as tiebreak",
+			input: ports.KeysetQuery{
+				Sort:  ports.Sort{Field: ports.SortByPrice, Desc: true},
+				After: &ports.Cursor{Sort: ports.Sort{Field: ports.SortByPrice, Desc: true}, LastId: 10, LastValue: "250000.50"},
+				Limit: 3,
+			},
+			mocking: func(mock sqlmock.Sqlmock, query ports.KeysetQuery) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(selectRealStates+` AND (real_state_price < ? OR (real_state_price = ? AND real_state_id < ?)) ORDER BY real_state_price DESC, real_state_id DESC LIMIT ?`)).
+					WithArgs(domain.NewMoney(25000050), domain.NewMoney(25000050), uint64(10), query.Limit).
+					WillReturnRows(sqlmock.NewRows(realStateColumns).
+						AddRow(4, 123456789, "123 Oak St", 100, 250000.50, "RJ", createdAt, updatedAt, "jane", "john", 3))
+
+				return output{
+					realStates: []domain.RealState{
+						{
+							Id:           4,
+							Registration: 123456789,
+							Address:      "123 Oak St",
+							Size:         100,
+							Price:        domain.NewMoney(25000050),
+							State:        "RJ",
+							CreatedAt:    createdAt,
+							UpdatedAt:    updatedAt,
+							CreatedBy:    "jane",
+							UpdatedBy:    "john",
+							Version:      3,
+						},
+					},
+					err: nil,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name: "When cursor value does not match the sort column, should return error without querying",
+			input: ports.KeysetQuery{
+				Sort:  ports.Sort{Field: ports.SortBySize},
+				After: &ports.Cursor{Sort: ports.Sort{Field: ports.SortBySize}, LastId: 10, LastValue: "big"},
+				Limit: 3,
+			},
+			mocking: func(mock sqlmock.Sqlmock, query ports.KeysetQuery) output {
+				return output{
+					realStates: nil,
+					err:        customerrors.BadRequest,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+		{
+			name:  "When select fails, should return error",
+			input: ports.KeysetQuery{Sort: ports.Sort{Field: ports.SortById}, Limit: 3},
+			mocking: func(mock sqlmock.Sqlmock, query ports.KeysetQuery) output {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(selectRealStates + ` ORDER BY real_state_id LIMIT ?`)).
+					WithArgs(query.Limit).
+					WillReturnError(sql.ErrConnDone)
+
+				return output{
+					realStates: nil,
+					err:        customerrors.Internal,
+				}
+			},
+			assertions: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, actual, expected)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 			}
 			defer db.Close()
 
 			expected := tc.mocking(mock, tc.input)
 
 			r := repository.NewRealStateRepository(db)
-			actual := r.DeleteRealState(ctx, tc.input)
+			var actual output
+			actual.realStates, actual.err = r.ListRealStatesAfter(ctx, tc.input)
 
 			tc.assertions(t, expected, actual)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRealStateRepositoryLogsDriverError(t *testing.T) {
+	testCases := []struct {
+		name      string
+		operation string
+		mocking   func(mock sqlmock.Sqlmock)
+		call      func(ctx context.Context, r ports.RealStateRepository) error
+		cause     string
+	}{
+		{
+			name:      "When query fails, should log the driver error under the operation",
+			operation: "GetRealState",
+			mocking: func(mock sqlmock.Sqlmock) {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.GetRealState)).
+					WithArgs(1).
+					WillReturnError(errors.New("connection refused"))
+			},
+			call: func(ctx context.Context, r ports.RealStateRepository) error {
+				_, err := r.GetRealState(ctx, 1)
+				return err
+			},
+			cause: "connection refused",
+		},
+		{
+			name:      "When listed rows fail, should log the error under the listing operation",
+			operation: "ListRealStatesAfter",
+			mocking: func(mock sqlmock.Sqlmock) {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(repository.ListRealStates)).
+					WillReturnRows(sqlmock.NewRows(realStateColumns).
+						AddRow(1, 987654321, "456 Elm St", 200, 250000.50, "SP", createdAt, updatedAt, "jane", "john", 3).
+						RowError(0, errors.New("connection reset")))
+			},
+			call: func(ctx context.Context, r ports.RealStateRepository) error {
+				_, err := r.ListRealStatesAfter(ctx, ports.KeysetQuery{Limit: 10})
+				return err
+			},
+			cause: "connection reset",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			tc.mocking(mock)
+
+			var out bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&out, nil)).With("request_id", "abc")
+			ctx := logging.NewContext(context.Background(), logger)
+
+			err = tc.call(ctx, repository.NewRealStateRepository(db))
+
+			assert.Equal(t, customerrors.Internal, err)
+
+			var record map[string]any
+			assert.NoError(t, json.Unmarshal(out.Bytes(), &record))
+			assert.Equal(t, "ERROR", record["level"])
+			assert.Equal(t, "abc", record["request_id"])
+			assert.Equal(t, tc.operation, record["operation"])
+			assert.Equal(t, tc.cause, record["error"])
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -0,0 +1,365 @@
+// Package repotest holds the behaviour every repository adapter must share,
+// for the adapters' tests to run against their own implementation.
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
+)
+
+func newHouse(registration uint64, price int64, state string) domain.RealState {
+	return domain.RealState{
+		Registration: registration,
+		Address:      "123 Main St",
+		Size:         100,
+		Price:        domain.NewMoney(price),
+		State:        state,
+	}
+}
+
+// TestRealStateRepository runs the contract against a fresh, empty repository
+// from newRepository for every case.
+func TestRealStateRepository(t *testing.T, newRepository func(t *testing.T) ports.RealStateRepository) {
+	ctx := identity.NewContext(context.Background(), identity.Caller{Id: "jane"})
+
+	testCases := []struct {
+		name string
+		run  func(t *testing.T, r ports.RealStateRepository)
+	}{
+		{
+			name: "When real state is created, should read it back as stored",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				created, err := r.CreateRealState(ctx, newHouse(1, 25000050, "SP"))
+				require.NoError(t, err)
+
+				actual, err := r.GetRealState(ctx, created.Id)
+
+				assert.NoError(t, err)
+				assert.Equal(t, created, actual)
+				assert.Equal(t, uint64(1), actual.Version)
+				assert.Equal(t, "jane", actual.CreatedBy)
+			},
+		},
+		{
+			name: "When registration is taken, should return conflict",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				_, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+
+				_, err = r.CreateRealState(ctx, newHouse(1, 200000, "RJ"))
+
+				assert.Equal(t, customerrors.Conflict, err)
+			},
+		},
+		{
+			name: "When updating to a taken registration, should return conflict",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				_, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+				other, err := r.CreateRealState(ctx, newHouse(2, 100000, "SP"))
+				require.NoError(t, err)
+
+				_, err = r.UpdateRealState(ctx, newHouse(1, 100000, "SP"), other.Id)
+
+				assert.Equal(t, customerrors.Conflict, err)
+			},
+		},
+		{
+			name: "When real states are created, should number them in order without reusing purged ids",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				first, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+				second, err := r.CreateRealState(ctx, newHouse(2, 100000, "SP"))
+				require.NoError(t, err)
+				assert.Greater(t, second.Id, first.Id)
+
+				require.NoError(t, r.DeleteRealState(ctx, second.Id, 0))
+				_, err = r.PurgeRealStates(ctx, time.Now().Add(time.Hour))
+				require.NoError(t, err)
+
+				third, err := r.CreateRealState(ctx, newHouse(2, 100000, "SP"))
+				require.NoError(t, err)
+				assert.Greater(t, third.Id, second.Id)
+			},
+		},
+		{
+			name: "When real state does not exist, should return not found",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				_, err := r.GetRealState(ctx, 42)
+				assert.Equal(t, customerrors.NotFound, err)
+
+				_, err = r.UpdateRealState(ctx, newHouse(1, 100000, "SP"), 42)
+				assert.Equal(t, customerrors.NotFound, err)
+
+				assert.Equal(t, customerrors.NotFound, r.DeleteRealState(ctx, 42, 0))
+
+				_, err = r.RestoreRealState(ctx, 42)
+				assert.Equal(t, customerrors.NotFound, err)
+			},
+		},
+		{
+			name: "When version matches, should update and record the new price",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				created, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+
+				update := newHouse(1, 120000, "SP")
+				update.Version = created.Version
+
+				updated, err := r.UpdateRealState(ctx, update, created.Id)
+				require.NoError(t, err)
+				assert.Equal(t, uint64(2), updated.Version)
+				assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+
+				stored, err := r.GetRealState(ctx, created.Id)
+				require.NoError(t, err)
+				assert.Equal(t, stored, updated)
+
+				points, err := r.ListPricePoints(ctx, created.Id)
+				require.NoError(t, err)
+				assert.Len(t, points, 2)
+				assert.Equal(t, "1200.00", points[1].Price.String())
+			},
+		},
+		{
+			name: "When values and price are unchanged, should still update without a new price point",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				created, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+
+				_, err = r.UpdateRealState(ctx, newHouse(1, 100000, "SP"), created.Id)
+				require.NoError(t, err)
+
+				points, err := r.ListPricePoints(ctx, created.Id)
+				require.NoError(t, err)
+				assert.Len(t, points, 1)
+			},
+		},
+		{
+			name: "When version is stale, should return precondition failed",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				created, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+
+				update := newHouse(1, 120000, "SP")
+				update.Version = created.Version + 1
+
+				_, err = r.UpdateRealState(ctx, update, created.Id)
+				assert.Equal(t, customerrors.PreconditionFailed, err)
+
+				assert.Equal(t, customerrors.PreconditionFailed, r.DeleteRealState(ctx, created.Id, created.Version+1))
+			},
+		},
+		{
+			name: "When real state is deleted, should hide it until restored",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				created, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+
+				require.NoError(t, r.DeleteRealState(ctx, created.Id, created.Version))
+
+				_, err = r.GetRealState(ctx, created.Id)
+				assert.Equal(t, customerrors.NotFound, err)
+
+				restored, err := r.RestoreRealState(ctx, created.Id)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(3), restored.Version)
+
+				stored, err := r.GetRealState(ctx, created.Id)
+				require.NoError(t, err)
+				assert.Equal(t, stored, restored)
+
+				_, err = r.RestoreRealState(ctx, created.Id)
+				assert.Equal(t, customerrors.NotFound, err)
+			},
+		},
+		{
+			name: "When purging, should only remove real states deleted before the cutoff",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				deleted, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+				live, err := r.CreateRealState(ctx, newHouse(2, 100000, "SP"))
+				require.NoError(t, err)
+
+				require.NoError(t, r.DeleteRealState(ctx, deleted.Id, 0))
+
+				purged, err := r.PurgeRealStates(ctx, time.Now().Add(-time.Hour))
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(0), purged)
+
+				purged, err = r.PurgeRealStates(ctx, time.Now().Add(time.Hour))
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(1), purged)
+
+				_, err = r.RestoreRealState(ctx, deleted.Id)
+				assert.Equal(t, customerrors.NotFound, err)
+
+				points, err := r.ListPricePoints(ctx, deleted.Id)
+				assert.NoError(t, err)
+				assert.Empty(t, points)
+
+				_, err = r.GetRealState(ctx, live.Id)
+				assert.NoError(t, err)
+				points, err = r.ListPricePoints(ctx, live.Id)
+				assert.NoError(t, err)
+				assert.Len(t, points, 1)
+			},
+		},
+		{
+			name: "When listing with filters and sort, should page through the matches",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				for i, price := range []int64{300000, 100000, 200000, 400000} {
+					_, err := r.CreateRealState(ctx, newHouse(uint64(i+1), price, "SP"))
+					require.NoError(t, err)
+				}
+				_, err := r.CreateRealState(ctx, newHouse(5, 150000, "RJ"))
+				require.NoError(t, err)
+
+				minPrice := domain.NewMoney(150000)
+				realStates, total, err := r.ListRealStates(ctx, ports.RealStateQuery{
+					Filter: ports.RealStateFilter{States: []string{"SP"}, MinPrice: &minPrice},
+					Sort:   []ports.Sort{{Field: ports.SortByPrice, Desc: true}},
+					Page:   ports.Page{Limit: 2, Offset: 1},
+				})
+
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(3), total)
+				assert.Equal(t, []uint64{1, 3}, registrations(realStates))
+			},
+		},
+		{
+			name: "When listing after a cursor, should continue past the last real state",
+			run: func(t *testing.T, r ports.RealStateRepository) {
+				for i, price := range []int64{300000, 100000, 300000, 200000} {
+					_, err := r.CreateRealState(ctx, newHouse(uint64(i+1), price, "SP"))
+					require.NoError(t, err)
+				}
+
+				sort := ports.Sort{Field: ports.SortByPrice}
+				first, err := r.ListRealStatesAfter(ctx, ports.KeysetQuery{Sort: sort, Limit: 3})
+				require.NoError(t, err)
+				assert.Equal(t, []uint64{2, 4, 1}, registrations(first))
+
+				last := first[len(first)-1]
+				rest, err := r.ListRealStatesAfter(ctx, ports.KeysetQuery{
+					Sort:  sort,
+					After: &ports.Cursor{Sort: sort, LastId: last.Id, LastValue: last.Price.String()},
+					Limit: 3,
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []uint64{3}, registrations(rest))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newRepository(t))
+		})
+	}
+}
+
+// TestAuditRepository runs the contract against the audit trail the real
+// state repository from newRepositories writes, fresh and empty for every
+// case.
+func TestAuditRepository(t *testing.T, newRepositories func(t *testing.T) (ports.RealStateRepository, ports.AuditRepository)) {
+	ctx := identity.NewContext(context.Background(), identity.Caller{Id: "jane"})
+
+	testCases := []struct {
+		name string
+		run  func(t *testing.T, r ports.RealStateRepository, a ports.AuditRepository)
+	}{
+		{
+			name: "When real state changes, should audit every change newest first",
+			run: func(t *testing.T, r ports.RealStateRepository, a ports.AuditRepository) {
+				created, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+				_, err = r.UpdateRealState(ctx, newHouse(1, 200000, "SP"), created.Id)
+				require.NoError(t, err)
+				require.NoError(t, r.DeleteRealState(ctx, created.Id, 0))
+				_, err = r.RestoreRealState(ctx, created.Id)
+				require.NoError(t, err)
+
+				entries, total, err := a.ListAudit(ctx, created.Id, ports.Page{Limit: 3})
+
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(4), total)
+				require.Len(t, entries, 3)
+				assert.Equal(t, domain.AuditRestore, entries[0].Operation)
+				assert.Equal(t, domain.AuditDelete, entries[1].Operation)
+				assert.Equal(t, domain.AuditUpdate, entries[2].Operation)
+				assert.Equal(t, "jane", entries[0].Actor)
+				if assert.Len(t, entries[2].Changes, 1) {
+					assert.Equal(t, "price", entries[2].Changes[0].Field)
+				}
+			},
+		},
+		{
+			name: "When real state is purged, should drop its audit trail only",
+			run: func(t *testing.T, r ports.RealStateRepository, a ports.AuditRepository) {
+				deleted, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+				live, err := r.CreateRealState(ctx, newHouse(2, 100000, "SP"))
+				require.NoError(t, err)
+				require.NoError(t, r.DeleteRealState(ctx, deleted.Id, 0))
+
+				_, err = r.PurgeRealStates(ctx, time.Now().Add(time.Hour))
+				require.NoError(t, err)
+
+				entries, total, err := a.ListAudit(ctx, deleted.Id, ports.Page{Limit: 10})
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(0), total)
+				assert.Empty(t, entries)
+
+				_, total, err = a.ListAudit(ctx, live.Id, ports.Page{Limit: 10})
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(1), total)
+			},
+		},
+		{
+			name: "When change fails, should not audit it",
+			run: func(t *testing.T, r ports.RealStateRepository, a ports.AuditRepository) {
+				_, err := r.CreateRealState(ctx, newHouse(1, 100000, "SP"))
+				require.NoError(t, err)
+				other, err := r.CreateRealState(ctx, newHouse(2, 100000, "SP"))
+				require.NoError(t, err)
+
+				_, err = r.UpdateRealState(ctx, newHouse(1, 100000, "SP"), other.Id)
+				require.Equal(t, customerrors.Conflict, err)
+				err = r.DeleteRealState(ctx, other.Id, other.Version+1)
+				require.Equal(t, customerrors.PreconditionFailed, err)
+
+				entries, total, err := a.ListAudit(ctx, other.Id, ports.Page{Limit: 10})
+
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(1), total)
+				assert.Equal(t, domain.AuditCreate, entries[0].Operation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, a := newRepositories(t)
+			tc.run(t, r, a)
+		})
+	}
+}
+
+func registrations(realStates []domain.RealState) []uint64 {
+	var result []uint64
+	for _, realState := range realStates {
+		result = append(result, realState.Registration)
+	}
+
+	return result
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"database/sql"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteOptions make every transaction take the write lock up front, so the
+// price read by UpdateRealState cannot go stale before it commits. Times keep
+// the driver's default layout, the only one that reads back in UTC.
+const sqliteOptions = "?_txlock=immediate&_pragma=busy_timeout(5000)"
+
+// OpenSQLite opens, creating it if needed, the SQLite database at path.
+// ":memory:" gives a private database that lives as long as the returned
+// handle.
+func OpenSQLite(path string) (*sql.DB, error) {
+	db, err := Open("sqlite", path+sqliteOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer, and an in-memory database only exists
+	// on the connection that created it.
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+
+	return db, db.Ping()
+}
@@ -0,0 +1,65 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/repository"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/migrate"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/repotest"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+)
+
+// openSQLite returns a migrated, empty in-memory database.
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := repository.OpenSQLite(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := migrate.SQLite()
+	require.NoError(t, err)
+
+	_, err = migrate.NewSQLite(db, migrations).Up(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestSQLiteRealStateRepository(t *testing.T) {
+	repotest.TestRealStateRepository(t, func(t *testing.T) ports.RealStateRepository {
+		return repository.NewSQLiteRealStateRepository(openSQLite(t))
+	})
+}
+
+func TestSQLiteAuditRepository(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) (ports.RealStateRepository, ports.AuditRepository) {
+		db := openSQLite(t)
+		return repository.NewSQLiteRealStateRepository(db), repository.NewAuditRepository(db)
+	})
+}
+
+func TestSQLitePriceIsStoredInCents(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	r := repository.NewSQLiteRealStateRepository(db)
+	created, err := r.CreateRealState(ctx, domain.RealState{Registration: 1, Address: "456 Elm St", Size: 200, Price: domain.NewMoney(25000050), State: "SP"})
+	require.NoError(t, err)
+
+	var (
+		storage string
+		cents   int64
+	)
+	err = db.QueryRow(`SELECT typeof(real_state_price), real_state_price FROM real_states WHERE real_state_id = ?`, created.Id).Scan(&storage, &cents)
+	require.NoError(t, err)
+
+	assert.Equal(t, "integer", storage)
+	assert.Equal(t, int64(25000050), cents)
+}
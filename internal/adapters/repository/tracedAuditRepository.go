@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/tracing"
+)
+
+type tracedAuditRepository struct {
+	next   ports.AuditRepository
+	tracer trace.Tracer
+}
+
+func NewTracedAuditRepository(next ports.AuditRepository, tp trace.TracerProvider) *tracedAuditRepository {
+	return &tracedAuditRepository{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (r *tracedAuditRepository) ListAudit(ctx context.Context, realStateId uint64, page ports.Page) ([]domain.AuditEntry, uint64, error) {
+	ctx, span := r.tracer.Start(ctx, "AuditRepository.ListAudit", trace.WithAttributes(tracing.RealStateId(realStateId)))
+	entries, total, err := r.next.ListAudit(ctx, realStateId, page)
+	tracing.EndSpan(span, err)
+	return entries, total, err
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/tracing"
+)
+
+const tracerName = "github.com/natanchagas/gin-crud/internal/adapters/repository"
+
+// tracedRealStateRepository opens a span around every call to the repository
+// it wraps, so the queries a call runs show up beneath it.
+type tracedRealStateRepository struct {
+	next   ports.RealStateRepository
+	tracer trace.Tracer
+}
+
+func NewTracedRealStateRepository(next ports.RealStateRepository, tp trace.TracerProvider) *tracedRealStateRepository {
+	return &tracedRealStateRepository{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (r *tracedRealStateRepository) CreateRealState(ctx context.Context, realState domain.RealState) (domain.RealState, error) {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.CreateRealState")
+	created, err := r.next.CreateRealState(ctx, realState)
+	tracing.EndSpan(span, err)
+	return created, err
+}
+
+func (r *tracedRealStateRepository) GetRealState(ctx context.Context, id uint64) (domain.RealState, error) {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.GetRealState", trace.WithAttributes(tracing.RealStateId(id)))
+	realState, err := r.next.GetRealState(ctx, id)
+	tracing.EndSpan(span, err)
+	return realState, err
+}
+
+func (r *tracedRealStateRepository) ListRealStates(ctx context.Context, query ports.RealStateQuery) ([]domain.RealState, uint64, error) {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.ListRealStates")
+	realStates, total, err := r.next.ListRealStates(ctx, query)
+	tracing.EndSpan(span, err)
+	return realStates, total, err
+}
+
+func (r *tracedRealStateRepository) ListRealStatesAfter(ctx context.Context, query ports.KeysetQuery) ([]domain.RealState, error) {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.ListRealStatesAfter")
+	realStates, err := r.next.ListRealStatesAfter(ctx, query)
+	tracing.EndSpan(span, err)
+	return realStates, err
+}
+
+func (r *tracedRealStateRepository) UpdateRealState(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error) {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.UpdateRealState", trace.WithAttributes(tracing.RealStateId(id)))
+	updated, err := r.next.UpdateRealState(ctx, realState, id)
+	tracing.EndSpan(span, err)
+	return updated, err
+}
+
+func (r *tracedRealStateRepository) DeleteRealState(ctx context.Context, id uint64, version uint64) error {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.DeleteRealState", trace.WithAttributes(tracing.RealStateId(id)))
+	err := r.next.DeleteRealState(ctx, id, version)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+func (r *tracedRealStateRepository) RestoreRealState(ctx context.Context, id uint64) (domain.RealState, error) {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.RestoreRealState", trace.WithAttributes(tracing.RealStateId(id)))
+	realState, err := r.next.RestoreRealState(ctx, id)
+	tracing.EndSpan(span, err)
+	return realState, err
+}
+
+func (r *tracedRealStateRepository) PurgeRealStates(ctx context.Context, deletedBefore time.Time) (uint64, error) {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.PurgeRealStates")
+	purged, err := r.next.PurgeRealStates(ctx, deletedBefore)
+	tracing.EndSpan(span, err)
+	return purged, err
+}
+
+func (r *tracedRealStateRepository) ListPricePoints(ctx context.Context, id uint64) ([]domain.PricePoint, error) {
+	ctx, span := r.tracer.Start(ctx, "RealStateRepository.ListPricePoints", trace.WithAttributes(tracing.RealStateId(id)))
+	points, err := r.next.ListPricePoints(ctx, id)
+	tracing.EndSpan(span, err)
+	return points, err
+}
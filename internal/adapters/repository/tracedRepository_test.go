@@ -0,0 +1,102 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/natanchagas/gin-crud/internal/adapters/repository"
+	"github.com/natanchagas/gin-crud/internal/adapters/repository/repotest"
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+)
+
+// recordSpans routes the global tracer provider, which traces the database
+// queries, to a span recorder until the test ends.
+func recordSpans(t *testing.T) (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return tp, recorder
+}
+
+func TestTracedRealStateRepository(t *testing.T) {
+	repotest.TestRealStateRepository(t, func(t *testing.T) ports.RealStateRepository {
+		return repository.NewTracedRealStateRepository(repository.NewMemoryRealStateRepository(), sdktrace.NewTracerProvider())
+	})
+}
+
+func TestTracedRealStateRepositorySpans(t *testing.T) {
+	ctx := context.Background()
+	tp, recorder := recordSpans(t)
+
+	r := repository.NewTracedRealStateRepository(repository.NewSQLiteRealStateRepository(openSQLite(t)), tp)
+
+	created, err := r.CreateRealState(ctx, domain.RealState{Registration: 1, Address: "456 Elm St", Size: 200, Price: domain.NewMoney(100), State: "SP"})
+	require.NoError(t, err)
+
+	ctx, request := tp.Tracer("test").Start(ctx, "PUT /realstate/:id")
+	updated := created
+	updated.Price = domain.NewMoney(200)
+	_, err = r.UpdateRealState(ctx, updated, created.Id)
+	require.NoError(t, err)
+	_, err = r.GetRealState(ctx, created.Id+1)
+	assert.Error(t, err)
+	request.End()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() != request.SpanContext().TraceID() {
+			continue
+		}
+
+		if span.Parent().SpanID() == request.SpanContext().SpanID() {
+			spans[span.Name()] = span
+		}
+	}
+
+	update := spans["RealStateRepository.UpdateRealState"]
+	require.NotNil(t, update)
+	assert.Contains(t, update.Attributes(), attribute.Int64("realstate.id", int64(created.Id)))
+	assert.Equal(t, codes.Unset, update.Status().Code)
+
+	get := spans["RealStateRepository.GetRealState"]
+	require.NotNil(t, get)
+	assert.Equal(t, codes.Error, get.Status().Code)
+
+	var statements []string
+	for _, span := range recorder.Ended() {
+		if span.Parent().SpanID() != update.SpanContext().SpanID() {
+			continue
+		}
+
+		for _, attr := range span.Attributes() {
+			if attr.Key == "db.statement" {
+				statements = append(statements, attr.Value.AsString())
+			}
+		}
+
+		assert.Contains(t, span.Attributes(), semconv.DBSystemSqlite)
+	}
+
+	assert.Equal(t, []string{
+		repository.LockRealState,
+		repository.UpdateRealState + repository.MatchVersion,
+		repository.CreatePricePoint,
+		repository.CreateAuditEntry,
+	}, statements)
+}
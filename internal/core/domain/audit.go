@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+type AuditOperation string
+
+const (
+	AuditCreate  AuditOperation = "create"
+	AuditUpdate  AuditOperation = "update"
+	AuditDelete  AuditOperation = "delete"
+	AuditRestore AuditOperation = "restore"
+)
+
+// auditedFields are the real state fields callers can change, in the order
+// their changes are reported. Metadata changes on every write and is already
+// captured by the entry itself.
+var auditedFields = []string{"registration", "address", "size", "price", "state"}
+
+// FieldChange holds the JSON values of a field before and after a change. A
+// missing side means the real state did not exist, or was deleted, then.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+type AuditEntry struct {
+	Id          uint64         `json:"id"`
+	RealStateId uint64         `json:"real_state_id"`
+	Operation   AuditOperation `json:"operation"`
+	Actor       string         `json:"actor"`
+	At          time.Time      `json:"at"`
+	Changes     []FieldChange  `json:"changes"`
+}
+
+// Diff lists the audited fields that differ between before and after. Either
+// side can be nil, for creations and deletions.
+func Diff(before, after *RealState) []FieldChange {
+	b, a := fieldValues(before), fieldValues(after)
+
+	changes := []FieldChange{}
+	for _, field := range auditedFields {
+		if bytes.Equal(b[field], a[field]) {
+			continue
+		}
+
+		changes = append(changes, FieldChange{Field: field, Before: b[field], After: a[field]})
+	}
+
+	return changes
+}
+
+func fieldValues(realState *RealState) map[string]json.RawMessage {
+	if realState == nil {
+		return nil
+	}
+
+	// A RealState always marshals, Money being its only custom type.
+	doc, _ := json.Marshal(realState)
+
+	var values map[string]json.RawMessage
+	_ = json.Unmarshal(doc, &values)
+
+	return values
+}
@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	stored := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(25000050),
+		State:        "SP",
+		Version:      3,
+	}
+
+	changed := stored
+	changed.Price = domain.NewMoney(26000000)
+	changed.State = "RJ"
+	changed.UpdatedBy = "john"
+	changed.Version = 4
+
+	testCases := []struct {
+		name     string
+		before   *domain.RealState
+		after    *domain.RealState
+		expected []domain.FieldChange
+	}{
+		{
+			name:   "When fields changed, should report only them with both values",
+			before: &stored,
+			after:  &changed,
+			expected: []domain.FieldChange{
+				{Field: "price", Before: json.RawMessage(`250000.50`), After: json.RawMessage(`260000.00`)},
+				{Field: "state", Before: json.RawMessage(`"SP"`), After: json.RawMessage(`"RJ"`)},
+			},
+		},
+		{
+			name:   "When real state is created, should report every field without a before value",
+			before: nil,
+			after:  &stored,
+			expected: []domain.FieldChange{
+				{Field: "registration", After: json.RawMessage(`987654321`)},
+				{Field: "address", After: json.RawMessage(`"456 Elm St"`)},
+				{Field: "size", After: json.RawMessage(`200`)},
+				{Field: "price", After: json.RawMessage(`250000.50`)},
+				{Field: "state", After: json.RawMessage(`"SP"`)},
+			},
+		},
+		{
+			name:   "When real state is deleted, should report every field without an after value",
+			before: &stored,
+			after:  nil,
+			expected: []domain.FieldChange{
+				{Field: "registration", Before: json.RawMessage(`987654321`)},
+				{Field: "address", Before: json.RawMessage(`"456 Elm St"`)},
+				{Field: "size", Before: json.RawMessage(`200`)},
+				{Field: "price", Before: json.RawMessage(`250000.50`)},
+				{Field: "state", Before: json.RawMessage(`"SP"`)},
+			},
+		},
+		{
+			name:     "When only metadata changed, should report no change",
+			before:   &stored,
+			after:    &domain.RealState{Id: 1, Registration: 987654321, Address: "456 Elm St", Size: 200, Price: stored.Price, State: "SP", Version: 9},
+			expected: []domain.FieldChange{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, domain.Diff(tc.before, tc.after))
+		})
+	}
+}
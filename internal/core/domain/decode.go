@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrMalformedRealState = errors.New("real state is not valid JSON")
+
+// DecodeRealState reads a real state strictly. Wrong types and unknown fields
+// come back as a ValidationError naming the field; syntax errors, which have
+// no field to point at, as ErrMalformedRealState. Server-managed metadata is
+// accepted, so a body fetched with GET can be sent back, but discarded.
+func DecodeRealState(r io.Reader) (RealState, error) {
+	var realState RealState
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(&realState)
+	if err == nil {
+		return realState.ClearMetadata(), nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" && typeErr.Type == reflect.TypeOf(Money{}) {
+			// Toolchains whose encoding/json runs on the v2 engine do not
+			// fill Field for errors returned by a custom UnmarshalJSON,
+			// and price is the only Money field.
+			field = "price"
+		}
+
+		return RealState{}, ValidationError{Fields: []FieldError{
+			{Field: field, Rule: RuleType, Message: "must be " + jsonType(typeErr.Type)},
+		}}
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		if unquoted, err := strconv.Unquote(field); err == nil {
+			field = unquoted
+		}
+
+		return RealState{}, ValidationError{Fields: []FieldError{
+			{Field: field, Rule: RuleUnknownField, Message: "is not a known field"},
+		}}
+	}
+
+	return RealState{}, ErrMalformedRealState
+}
+
+func jsonType(t reflect.Type) string {
+	if t == reflect.TypeOf(Money{}) {
+		return "a decimal number with at most two decimal places"
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	default:
+		return "a " + t.Kind().String()
+	}
+}
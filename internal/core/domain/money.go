@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultCurrency = "BRL"
+	moneyScale      = 2
+	minorPerUnit    = 100
+)
+
+var (
+	ErrInvalidMoney     = errors.New("money must be a decimal number with at most two decimal places")
+	ErrCurrencyMismatch = errors.New("money currencies do not match")
+	ErrMoneyOverflow    = errors.New("money amount overflows")
+)
+
+// MaxPrice is the largest amount the DECIMAL(15,2) price column holds.
+var MaxPrice = Money{amount: 999_999_999_999_999}
+
+// Money is a fixed-point amount kept in minor units (cents), matching the
+// DECIMAL(15,2) price column so values round-trip without float drift. The
+// currency defaults to DefaultCurrency, so the zero value is zero reais and
+// prices read from JSON or the database compare equal to NewMoney.
+type Money struct {
+	amount int64
+	// currency is empty for DefaultCurrency.
+	currency string
+}
+
+// NewMoney returns minor cents of DefaultCurrency.
+func NewMoney(minor int64) Money {
+	return Money{amount: minor}
+}
+
+// NewMoneyIn returns minor cents of the given ISO 4217 currency code.
+func NewMoneyIn(minor int64, currency string) Money {
+	if currency == DefaultCurrency {
+		currency = ""
+	}
+
+	return Money{amount: minor, currency: currency}
+}
+
+// ParseMoney reads a plain decimal such as "250000.5" or "-10.25" in
+// DefaultCurrency. More than two decimal places is rejected rather than
+// rounded.
+func ParseMoney(value string) (Money, error) {
+	s := strings.TrimSpace(value)
+
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = s[1:]
+	} else if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && frac == "") || len(frac) > moneyScale || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, ErrInvalidMoney
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/minorPerUnit {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	for len(frac) < moneyScale {
+		frac += "0"
+	}
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+
+	amount := units*minorPerUnit + cents
+	if amount < 0 {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return Money{amount: amount}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Amount returns the value in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if m.currency != o.currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	sum := m.amount + o.amount
+	if (o.amount > 0 && sum < m.amount) || (o.amount < 0 && sum > m.amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{amount: sum, currency: m.currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if m.currency != o.currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	if o.amount == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return m.Add(Money{amount: -o.amount, currency: o.currency})
+}
+
+func (m Money) Mul(n int64) (Money, error) {
+	product := m.amount * n
+	if n != 0 && (product/n != m.amount || (n == -1 && m.amount == math.MinInt64)) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{amount: product, currency: m.currency}, nil
+}
+
+// Cmp orders amounts of the same currency. Amounts in different currencies
+// have no order, so comparing them is a programming error and panics with
+// ErrCurrencyMismatch.
+func (m Money) Cmp(o Money) int {
+	if m.currency != o.currency {
+		panic(ErrCurrencyMismatch)
+	}
+
+	switch {
+	case m.amount < o.amount:
+		return -1
+	case m.amount > o.amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String renders the plain decimal with exactly two places, e.g. "250000.50".
+func (m Money) String() string {
+	sign := ""
+	abs := uint64(m.amount)
+	if m.amount < 0 {
+		sign = "-"
+		abs = uint64(-(m.amount + 1)) + 1
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, abs/minorPerUnit, abs%minorPerUnit)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a number or a numeric string. The literal is parsed as
+// text, never through float64, so 250000.5 stays exactly 25000050 cents.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	value := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseMoney(value)
+	if err != nil {
+		return &json.UnmarshalTypeError{Value: "number " + value, Type: reflect.TypeOf(Money{})}
+	}
+
+	*m = parsed
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) Scan(src any) error {
+	var value string
+
+	switch v := src.(type) {
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case float64:
+		value = strconv.FormatFloat(v, 'f', moneyScale, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into money", src)
+	}
+
+	parsed, err := ParseMoney(value)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
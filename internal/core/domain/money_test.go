@@ -0,0 +1,287 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		assertion func(t *testing.T, m domain.Money, err error)
+	}{
+		{
+			name:  "When value has one decimal place, should keep it exact in cents",
+			input: "250000.5",
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(25000050), m.Amount())
+				assert.Equal(t, "250000.50", m.String())
+			},
+		},
+		{
+			name:  "When value is a negative integer, should scale it to cents",
+			input: "-10",
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(-1000), m.Amount())
+				assert.Equal(t, "-10.00", m.String())
+			},
+		},
+		{
+			name:  "When value has more than two decimal places, should fail",
+			input: "0.001",
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.ErrorIs(t, err, domain.ErrInvalidMoney)
+			},
+		},
+		{
+			name:  "When value is in exponent form, should fail",
+			input: "1e3",
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.ErrorIs(t, err, domain.ErrInvalidMoney)
+			},
+		},
+		{
+			name:  "When value does not fit in cents, should fail",
+			input: "92233720368547758.08",
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.ErrorIs(t, err, domain.ErrMoneyOverflow)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := domain.ParseMoney(tc.input)
+			tc.assertion(t, m, err)
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		assertion func(t *testing.T, m domain.Money, err error)
+	}{
+		{
+			name:  "When price is a number, should decode without float drift and encode with two places",
+			input: `{"price":0.29}`,
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.NewMoney(29), m)
+
+				b, err := json.Marshal(struct {
+					Price domain.Money `json:"price"`
+				}{m})
+				assert.NoError(t, err)
+				assert.Equal(t, `{"price":0.29}`, string(b))
+			},
+		},
+		{
+			name:  "When price is a numeric string, should decode it",
+			input: `{"price":"1500.75"}`,
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.NewMoney(150075), m)
+			},
+		},
+		{
+			name:  "When price is not a decimal, should return a type error",
+			input: `{"price":true}`,
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				var typeErr *json.UnmarshalTypeError
+				assert.ErrorAs(t, err, &typeErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var v struct {
+				Price domain.Money `json:"price"`
+			}
+
+			err := json.Unmarshal([]byte(tc.input), &v)
+			tc.assertion(t, v.Price, err)
+		})
+	}
+}
+
+func TestMoneySQL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     any
+		assertion func(t *testing.T, m domain.Money, err error)
+	}{
+		{
+			name:  "When driver returns decimal bytes, should scan them exactly",
+			input: []byte("250000.50"),
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.NewMoney(25000050), m)
+
+				v, err := m.Value()
+				assert.NoError(t, err)
+				assert.Equal(t, "250000.50", v)
+			},
+		},
+		{
+			name:  "When driver returns a float, should round it to cents",
+			input: 0.1 + 0.2,
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(30), m.Amount())
+			},
+		},
+		{
+			name:  "When driver returns an unsupported type, should fail",
+			input: true,
+			assertion: func(t *testing.T, m domain.Money, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var m domain.Money
+			err := m.Scan(tc.input)
+			tc.assertion(t, m, err)
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	brl := domain.NewMoney
+
+	testCases := []struct {
+		name      string
+		operation func() (domain.Money, error)
+		expected  domain.Money
+		err       error
+	}{
+		{
+			name:      "When adding, should sum the cents",
+			operation: func() (domain.Money, error) { return brl(10).Add(brl(20)) },
+			expected:  brl(30),
+		},
+		{
+			name:      "When subtracting past zero, should go negative",
+			operation: func() (domain.Money, error) { return brl(10).Sub(brl(25)) },
+			expected:  brl(-15),
+		},
+		{
+			name:      "When multiplying, should scale the cents",
+			operation: func() (domain.Money, error) { return brl(333).Mul(3) },
+			expected:  brl(999),
+		},
+		{
+			name:      "When adding to the zero value, should sum the cents",
+			operation: func() (domain.Money, error) { return domain.Money{}.Add(brl(10)) },
+			expected:  brl(10),
+		},
+		{
+			name:      "When adding a different currency, should fail",
+			operation: func() (domain.Money, error) { return brl(10).Add(domain.NewMoneyIn(10, "USD")) },
+			err:       domain.ErrCurrencyMismatch,
+		},
+		{
+			name:      "When subtracting a different currency, should fail",
+			operation: func() (domain.Money, error) { return domain.NewMoneyIn(10, "USD").Sub(brl(10)) },
+			err:       domain.ErrCurrencyMismatch,
+		},
+		{
+			name:      "When both amounts are in the same non-default currency, should keep it",
+			operation: func() (domain.Money, error) { return domain.NewMoneyIn(10, "USD").Add(domain.NewMoneyIn(5, "USD")) },
+			expected:  domain.NewMoneyIn(15, "USD"),
+		},
+		{
+			name:      "When the sum overflows, should fail",
+			operation: func() (domain.Money, error) { return brl(1 << 62).Add(brl(1 << 62)) },
+			err:       domain.ErrMoneyOverflow,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := tc.operation()
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMoneyCurrency(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    domain.Money
+		expected string
+	}{
+		{
+			name:     "When money is the zero value, should be in the default currency",
+			input:    domain.Money{},
+			expected: domain.DefaultCurrency,
+		},
+		{
+			name:     "When money is built without a currency, should be in the default currency",
+			input:    domain.NewMoney(1000),
+			expected: domain.DefaultCurrency,
+		},
+		{
+			name:     "When money is built in another currency, should keep it",
+			input:    domain.NewMoneyIn(1000, "USD"),
+			expected: "USD",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.input.Currency())
+		})
+	}
+}
+
+func TestMoneyCmp(t *testing.T) {
+	testCases := []struct {
+		name      string
+		a, b      domain.Money
+		assertion func(t *testing.T, cmp func() int)
+	}{
+		{
+			name: "When money is built in the default currency, should equal money built without one",
+			a:    domain.NewMoneyIn(1000, domain.DefaultCurrency),
+			b:    domain.NewMoney(1000),
+			assertion: func(t *testing.T, cmp func() int) {
+				assert.Equal(t, 0, cmp())
+			},
+		},
+		{
+			name: "When the first amount is smaller, should return -1",
+			a:    domain.NewMoney(999),
+			b:    domain.NewMoney(1000),
+			assertion: func(t *testing.T, cmp func() int) {
+				assert.Equal(t, -1, cmp())
+			},
+		},
+		{
+			name: "When currencies differ, should panic",
+			a:    domain.NewMoney(1000),
+			b:    domain.NewMoneyIn(1000, "USD"),
+			assertion: func(t *testing.T, cmp func() int) {
+				assert.PanicsWithValue(t, domain.ErrCurrencyMismatch, func() { cmp() })
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.assertion(t, func() int { return tc.a.Cmp(tc.b) })
+		})
+	}
+}
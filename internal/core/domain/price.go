@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+type PricePoint struct {
+	Price Money     `json:"price"`
+	At    time.Time `json:"at"`
+}
+
+// PriceHistory is the series of prices of a real state, oldest first, with
+// how much the current price moved from the listing price.
+type PriceHistory struct {
+	RealStateId   uint64       `json:"real_state_id"`
+	Prices        []PricePoint `json:"prices"`
+	ListingPrice  Money        `json:"listing_price"`
+	CurrentPrice  Money        `json:"current_price"`
+	Change        Money        `json:"change"`
+	ChangePercent float64      `json:"change_percent"`
+}
+
+// NewPriceHistory summarizes points, which must hold at least the listing
+// price. The percentage is rounded to two decimal places.
+func NewPriceHistory(realStateId uint64, points []PricePoint) (PriceHistory, error) {
+	listing, current := points[0].Price, points[len(points)-1].Price
+
+	change, err := current.Sub(listing)
+	if err != nil {
+		return PriceHistory{}, err
+	}
+
+	var percent float64
+	if !listing.IsZero() {
+		percent = math.Round(float64(change.Amount())/float64(listing.Amount())*10000) / 100
+	}
+
+	return PriceHistory{
+		RealStateId:   realStateId,
+		Prices:        points,
+		ListingPrice:  listing,
+		CurrentPrice:  current,
+		Change:        change,
+		ChangePercent: percent,
+	}, nil
+}
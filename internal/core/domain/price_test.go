@@ -0,0 +1,63 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPriceHistory(t *testing.T) {
+	listedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	point := func(cents int64, days int) domain.PricePoint {
+		return domain.PricePoint{Price: domain.NewMoney(cents), At: listedAt.AddDate(0, 0, days)}
+	}
+
+	testCases := []struct {
+		name      string
+		input     []domain.PricePoint
+		assertion func(t *testing.T, actual domain.PriceHistory, err error)
+	}{
+		{
+			name:  "When price went up, should report a positive change since listing",
+			input: []domain.PricePoint{point(25000000, 0), point(24000000, 10), point(26000000, 20)},
+			assertion: func(t *testing.T, actual domain.PriceHistory, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.NewMoney(25000000), actual.ListingPrice)
+				assert.Equal(t, domain.NewMoney(26000000), actual.CurrentPrice)
+				assert.Equal(t, domain.NewMoney(1000000), actual.Change)
+				assert.Equal(t, 4.0, actual.ChangePercent)
+				assert.Len(t, actual.Prices, 3)
+			},
+		},
+		{
+			name:  "When price went down, should report a negative change rounded to two places",
+			input: []domain.PricePoint{point(30000000, 0), point(20000000, 5)},
+			assertion: func(t *testing.T, actual domain.PriceHistory, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.NewMoney(-10000000), actual.Change)
+				assert.Equal(t, -33.33, actual.ChangePercent)
+			},
+		},
+		{
+			name:  "When only the listing price exists, should report no change",
+			input: []domain.PricePoint{point(25000050, 0)},
+			assertion: func(t *testing.T, actual domain.PriceHistory, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, actual.ListingPrice, actual.CurrentPrice)
+				assert.True(t, actual.Change.IsZero())
+				assert.Equal(t, 0.0, actual.ChangePercent)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := domain.NewPriceHistory(1, tc.input)
+
+			tc.assertion(t, actual, err)
+		})
+	}
+}
@@ -1,10 +1,79 @@
 package domain
 
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRegistration is the largest registration the INT registration column
+// holds.
+const MaxRegistration = math.MaxInt32
+
+// MaxSize is the largest whole size the DECIMAL(10,2) size column holds.
+const MaxSize = 99_999_999
+
 type RealState struct {
-	Id           uint64  `json:"id,omitempty"`
-	Registration uint64  `json:"registration"`
-	Address      string  `json:"address"`
-	Size         uint64  `json:"size"`
-	Price        float64 `json:"price"`
-	State        string  `json:"state"`
+	Id           uint64    `json:"id,omitempty"`
+	Registration uint64    `json:"registration"`
+	Address      string    `json:"address"`
+	Size         uint64    `json:"size"`
+	Price        Money     `json:"price"`
+	State        string    `json:"state"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	CreatedBy    string    `json:"created_by"`
+	UpdatedBy    string    `json:"updated_by"`
+	Version      uint64    `json:"version"`
+}
+
+// ClearMetadata drops the fields the repository manages, so whatever a client
+// sent for them never reaches storage or the response.
+func (r RealState) ClearMetadata() RealState {
+	r.CreatedAt = time.Time{}
+	r.UpdatedAt = time.Time{}
+	r.CreatedBy = ""
+	r.UpdatedBy = ""
+	r.Version = 0
+
+	return r
+}
+
+// Validate checks every field and reports all failures at once, so callers
+// can show the whole list instead of fixing one field per request.
+func (r RealState) Validate() error {
+	var fields []FieldError
+
+	if r.Registration == 0 {
+		fields = append(fields, FieldError{Field: "registration", Rule: RuleRequired, Message: "must be greater than zero"})
+	} else if r.Registration > MaxRegistration {
+		fields = append(fields, FieldError{Field: "registration", Rule: RuleRange, Message: "must be at most " + strconv.Itoa(MaxRegistration)})
+	}
+
+	if strings.TrimSpace(r.Address) == "" {
+		fields = append(fields, FieldError{Field: "address", Rule: RuleRequired, Message: "must not be empty"})
+	}
+
+	if r.Size == 0 {
+		fields = append(fields, FieldError{Field: "size", Rule: RulePositive, Message: "must be greater than zero"})
+	} else if r.Size > MaxSize {
+		fields = append(fields, FieldError{Field: "size", Rule: RuleRange, Message: "must be at most " + strconv.Itoa(MaxSize)})
+	}
+
+	if !r.Price.IsPositive() {
+		fields = append(fields, FieldError{Field: "price", Rule: RulePositive, Message: "must be greater than zero"})
+	} else if r.Price.Cmp(MaxPrice) > 0 {
+		fields = append(fields, FieldError{Field: "price", Rule: RuleRange, Message: "must be at most " + MaxPrice.String()})
+	}
+
+	if !IsBrazilianState(r.State) {
+		fields = append(fields, FieldError{Field: "state", Rule: RuleState, Message: "must be a brazilian state code"})
+	}
+
+	if len(fields) > 0 {
+		return ValidationError{Fields: fields}
+	}
+
+	return nil
 }
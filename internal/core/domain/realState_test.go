@@ -0,0 +1,112 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     domain.RealState
+		assertion func(t *testing.T, err error)
+	}{
+		{
+			name: "When every field is valid, should return nil",
+			input: domain.RealState{
+				Registration: 987654321,
+				Address:      "456 Elm St",
+				Size:         200,
+				Price:        domain.NewMoney(25000050),
+				State:        "SP",
+			},
+			assertion: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:  "When every field is invalid, should report all of them",
+			input: domain.RealState{Address: "   ", State: "XYZ"},
+			assertion: func(t *testing.T, err error) {
+				assert.Equal(t, domain.ValidationError{
+					Fields: []domain.FieldError{
+						{Field: "registration", Rule: domain.RuleRequired, Message: "must be greater than zero"},
+						{Field: "address", Rule: domain.RuleRequired, Message: "must not be empty"},
+						{Field: "size", Rule: domain.RulePositive, Message: "must be greater than zero"},
+						{Field: "price", Rule: domain.RulePositive, Message: "must be greater than zero"},
+						{Field: "state", Rule: domain.RuleState, Message: "must be a brazilian state code"},
+					},
+				}, err)
+			},
+		},
+		{
+			name: "When state is not a brazilian state, should report only the state",
+			input: domain.RealState{
+				Registration: 987654321,
+				Address:      "456 Elm St",
+				Size:         200,
+				Price:        domain.NewMoney(25000050),
+				State:        "CA",
+			},
+			assertion: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "invalid real state: state must be a brazilian state code")
+			},
+		},
+		{
+			name: "When price is negative, should report the price",
+			input: domain.RealState{
+				Registration: 987654321,
+				Address:      "456 Elm St",
+				Size:         200,
+				Price:        domain.NewMoney(-100),
+				State:        "SP",
+			},
+			assertion: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "invalid real state: price must be greater than zero")
+			},
+		},
+		{
+			name: "When price does not fit the price column, should report the price",
+			input: domain.RealState{
+				Registration: 987654321,
+				Address:      "456 Elm St",
+				Size:         200,
+				Price:        domain.NewMoney(1_000_000_000_000_000),
+				State:        "SP",
+			},
+			assertion: func(t *testing.T, err error) {
+				var verr domain.ValidationError
+				assert.ErrorAs(t, err, &verr)
+				assert.Equal(t, []domain.FieldError{
+					{Field: "price", Rule: domain.RuleRange, Message: "must be at most 9999999999999.99"},
+				}, verr.Fields)
+			},
+		},
+		{
+			name: "When registration and size do not fit their columns, should report both",
+			input: domain.RealState{
+				Registration: 18446744073709551615,
+				Address:      "456 Elm St",
+				Size:         100_000_000,
+				Price:        domain.NewMoney(25000050),
+				State:        "SP",
+			},
+			assertion: func(t *testing.T, err error) {
+				var verr domain.ValidationError
+				assert.ErrorAs(t, err, &verr)
+				assert.Equal(t, []domain.FieldError{
+					{Field: "registration", Rule: domain.RuleRange, Message: "must be at most 2147483647"},
+					{Field: "size", Rule: domain.RuleRange, Message: "must be at most 99999999"},
+				}, verr.Fields)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.assertion(t, tc.input.Validate())
+		})
+	}
+}
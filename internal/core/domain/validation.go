@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+)
+
+const (
+	RuleRequired     = "required"
+	RulePositive     = "positive"
+	RuleRange        = "range"
+	RuleState        = "state"
+	RuleType         = "type"
+	RuleUnknownField = "unknown_field"
+)
+
+var brazilianStates = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + " " + f.Message
+	}
+
+	return "invalid real state: " + strings.Join(messages, "; ")
+}
+
+// IsBrazilianState reports whether state is one of the 27 federative unit codes.
+func IsBrazilianState(state string) bool {
+	return brazilianStates[state]
+}
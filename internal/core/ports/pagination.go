@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+)
+
+const (
+	DefaultPageLimit uint64 = 20
+	MaxPageLimit     uint64 = 100
+)
+
+type Page struct {
+	Limit  uint64
+	Offset uint64
+}
+
+type RealStatePage struct {
+	RealStates []domain.RealState
+	Total      uint64
+	Page       Page
+}
+
+type AuditPage struct {
+	Entries []domain.AuditEntry
+	Total   uint64
+	Page    Page
+}
+
+type Cursor struct {
+	Sort      Sort
+	LastId    uint64
+	LastValue string
+}
+
+type KeysetQuery struct {
+	Sort  Sort
+	After *Cursor
+	Limit uint64
+}
+
+type RealStateKeysetPage struct {
+	RealStates []domain.RealState
+	Next       *Cursor
+	Limit      uint64
+}
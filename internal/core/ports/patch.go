@@ -0,0 +1,16 @@
+package ports
+
+type PatchFormat string
+
+const (
+	MergePatch PatchFormat = "application/merge-patch+json"
+	JSONPatch  PatchFormat = "application/json-patch+json"
+)
+
+// RealStatePatch is a change document applied to the stored real state.
+// Version, when not zero, is the version the client based the patch on.
+type RealStatePatch struct {
+	Format   PatchFormat
+	Document []byte
+	Version  uint64
+}
@@ -2,14 +2,25 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/natanchagas/gin-crud/internal/core/domain"
 )
 
 //go:generate mockery --name RealStateRepository
 type RealStateRepository interface {
-	CreateRealState(ctx context.Context, realState domain.RealState) (int64, error)
+	CreateRealState(ctx context.Context, realState domain.RealState) (domain.RealState, error)
 	GetRealState(ctx context.Context, id uint64) (domain.RealState, error)
+	ListRealStates(ctx context.Context, query RealStateQuery) ([]domain.RealState, uint64, error)
+	ListRealStatesAfter(ctx context.Context, query KeysetQuery) ([]domain.RealState, error)
 	UpdateRealState(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error)
-	DeleteRealState(ctx context.Context, id uint64) error
+	DeleteRealState(ctx context.Context, id uint64, version uint64) error
+	RestoreRealState(ctx context.Context, id uint64) (domain.RealState, error)
+	PurgeRealStates(ctx context.Context, deletedBefore time.Time) (uint64, error)
+	ListPricePoints(ctx context.Context, id uint64) ([]domain.PricePoint, error)
+}
+
+//go:generate mockery --name AuditRepository
+type AuditRepository interface {
+	ListAudit(ctx context.Context, realStateId uint64, page Page) ([]domain.AuditEntry, uint64, error)
 }
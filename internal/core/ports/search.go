@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+)
+
+type SortField string
+
+const (
+	SortById           SortField = "id"
+	SortByRegistration SortField = "registration"
+	SortByPrice        SortField = "price"
+	SortBySize         SortField = "size"
+	SortByState        SortField = "state"
+)
+
+var SortableFields = []SortField{
+	SortById,
+	SortByRegistration,
+	SortByPrice,
+	SortBySize,
+	SortByState,
+}
+
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+type RealStateFilter struct {
+	States       []string
+	MinPrice     *domain.Money
+	MaxPrice     *domain.Money
+	MinSize      *uint64
+	MaxSize      *uint64
+	Registration *uint64
+	Address      string
+}
+
+type RealStateQuery struct {
+	Filter RealStateFilter
+	Sort   []Sort
+	Page   Page
+}
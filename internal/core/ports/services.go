@@ -10,6 +10,14 @@ import (
 type RealStateService interface {
 	Create(ctx context.Context, realState domain.RealState) (domain.RealState, error)
 	Get(ctx context.Context, id uint64) (domain.RealState, error)
+	List(ctx context.Context, page Page) (RealStatePage, error)
+	Search(ctx context.Context, query RealStateQuery) (RealStatePage, error)
+	ListAfter(ctx context.Context, query KeysetQuery) (RealStateKeysetPage, error)
 	Update(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error)
-	Delete(ctx context.Context, id uint64) error
+	Patch(ctx context.Context, patch RealStatePatch, id uint64) (domain.RealState, error)
+	Delete(ctx context.Context, id uint64, version uint64) error
+	Restore(ctx context.Context, id uint64) (domain.RealState, error)
+	Purge(ctx context.Context) (uint64, error)
+	History(ctx context.Context, id uint64, page Page) (AuditPage, error)
+	Prices(ctx context.Context, id uint64) (domain.PriceHistory, error)
 }
@@ -1,31 +1,49 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/natanchagas/gin-crud/internal/core/domain"
 	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
 )
 
+// DefaultPurgeRetention applies when PurgeRetention is not configured.
+const DefaultPurgeRetention = 30 * 24 * time.Hour
+
 type realStateService struct {
 	repository ports.RealStateRepository
+	audit      ports.AuditRepository
+
+	// PurgeRetention is how long a deleted real state can still be restored
+	// before Purge removes it.
+	PurgeRetention time.Duration
 }
 
-func NewRealStateService(r ports.RealStateRepository) *realStateService {
+func NewRealStateService(r ports.RealStateRepository, a ports.AuditRepository) *realStateService {
 	return &realStateService{
 		repository: r,
+		audit:      a,
 	}
 }
 
 func (s *realStateService) Create(ctx context.Context, realState domain.RealState) (domain.RealState, error) {
-	id, err := s.repository.CreateRealState(ctx, realState)
+	if err := realState.Validate(); err != nil {
+		return domain.RealState{}, err
+	}
 
+	realState, err := s.repository.CreateRealState(ctx, realState)
 	if err != nil {
 		return domain.RealState{}, err
 	}
 
-	realState.Id = uint64(id)
-
 	return realState, nil
 }
 
@@ -38,14 +56,104 @@ func (s *realStateService) Get(ctx context.Context, id uint64) (domain.RealState
 	return realState, nil
 }
 
+func (s *realStateService) List(ctx context.Context, page ports.Page) (ports.RealStatePage, error) {
+	return s.Search(ctx, ports.RealStateQuery{Page: page})
+}
+
+func (s *realStateService) Search(ctx context.Context, query ports.RealStateQuery) (ports.RealStatePage, error) {
+	realStates, total, err := s.repository.ListRealStates(ctx, query)
+	if err != nil {
+		return ports.RealStatePage{}, err
+	}
+
+	return ports.RealStatePage{
+		RealStates: realStates,
+		Total:      total,
+		Page:       query.Page,
+	}, nil
+}
+
+func (s *realStateService) ListAfter(ctx context.Context, query ports.KeysetQuery) (ports.RealStateKeysetPage, error) {
+	limit := query.Limit
+
+	// One extra row tells whether there is a next page without counting.
+	query.Limit = limit + 1
+
+	realStates, err := s.repository.ListRealStatesAfter(ctx, query)
+	if err != nil {
+		return ports.RealStateKeysetPage{}, err
+	}
+
+	page := ports.RealStateKeysetPage{
+		RealStates: realStates,
+		Limit:      limit,
+	}
+
+	if uint64(len(realStates)) > limit {
+		page.RealStates = realStates[:limit]
+		last := page.RealStates[len(page.RealStates)-1]
+
+		page.Next = &ports.Cursor{
+			Sort:      query.Sort,
+			LastId:    last.Id,
+			LastValue: sortValue(query.Sort.Field, last),
+		}
+	}
+
+	return page, nil
+}
+
 func (s *realStateService) Update(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error) {
-	_, err := s.repository.GetRealState(ctx, id)
+	if err := realState.Validate(); err != nil {
+		return domain.RealState{}, err
+	}
+
+	realState, err := s.repository.UpdateRealState(ctx, realState, id)
+	if err != nil {
+		return domain.RealState{}, err
+	}
+
+	return realState, nil
+}
+
+// Patch applies the change document to the stored real state and writes the
+// result back against the version it was read at, so a concurrent update is
+// reported instead of overwritten.
+func (s *realStateService) Patch(ctx context.Context, patch ports.RealStatePatch, id uint64) (domain.RealState, error) {
+	current, err := s.repository.GetRealState(ctx, id)
 	if err != nil {
 		return domain.RealState{}, err
 	}
 
+	if patch.Version != 0 && patch.Version != current.Version {
+		return domain.RealState{}, customerrors.PreconditionFailed
+	}
+
+	realState, err := applyPatch(ctx, current, patch)
+	if err != nil {
+		return domain.RealState{}, err
+	}
+
+	if err := realState.Validate(); err != nil {
+		return domain.RealState{}, err
+	}
+
+	realState.Version = current.Version
+
 	realState, err = s.repository.UpdateRealState(ctx, realState, id)
+	if err != nil {
+		return domain.RealState{}, err
+	}
+
+	return realState, nil
+}
 
+func (s *realStateService) Delete(ctx context.Context, id uint64, version uint64) error {
+	return s.repository.DeleteRealState(ctx, id, version)
+}
+
+func (s *realStateService) Restore(ctx context.Context, id uint64) (domain.RealState, error) {
+	realState, err := s.repository.RestoreRealState(ctx, id)
 	if err != nil {
 		return domain.RealState{}, err
 	}
@@ -53,6 +161,107 @@ func (s *realStateService) Update(ctx context.Context, realState domain.RealStat
 	return realState, nil
 }
 
-func (s *realStateService) Delete(ctx context.Context, id uint64) error {
-	return s.repository.DeleteRealState(ctx, id)
+// Purge is reserved to admins since purged real states cannot be restored.
+func (s *realStateService) Purge(ctx context.Context) (uint64, error) {
+	if !identity.HasRole(ctx, identity.RoleAdmin) {
+		return 0, customerrors.Forbidden
+	}
+
+	retention := s.PurgeRetention
+	if retention <= 0 {
+		retention = DefaultPurgeRetention
+	}
+
+	return s.repository.PurgeRealStates(ctx, time.Now().Add(-retention))
+}
+
+// History lists the changes made to a real state, newest first. It keeps
+// working after the real state is deleted, until it is purged.
+func (s *realStateService) History(ctx context.Context, id uint64, page ports.Page) (ports.AuditPage, error) {
+	entries, total, err := s.audit.ListAudit(ctx, id, page)
+	if err != nil {
+		return ports.AuditPage{}, err
+	}
+
+	return ports.AuditPage{
+		Entries: entries,
+		Total:   total,
+		Page:    page,
+	}, nil
+}
+
+// Prices only covers real states that are not deleted. Those stored before
+// prices were tracked get their current price as the listing price.
+func (s *realStateService) Prices(ctx context.Context, id uint64) (domain.PriceHistory, error) {
+	realState, err := s.repository.GetRealState(ctx, id)
+	if err != nil {
+		return domain.PriceHistory{}, err
+	}
+
+	points, err := s.repository.ListPricePoints(ctx, id)
+	if err != nil {
+		return domain.PriceHistory{}, err
+	}
+
+	if len(points) == 0 {
+		points = []domain.PricePoint{{Price: realState.Price, At: realState.CreatedAt}}
+	}
+
+	history, err := domain.NewPriceHistory(id, points)
+	if err != nil {
+		return domain.PriceHistory{}, internalError(ctx, "Prices", err)
+	}
+
+	return history, nil
+}
+
+func applyPatch(ctx context.Context, current domain.RealState, patch ports.RealStatePatch) (domain.RealState, error) {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return domain.RealState{}, internalError(ctx, "Patch", err)
+	}
+
+	var patched []byte
+
+	switch patch.Format {
+	case ports.MergePatch:
+		patched, err = jsonpatch.MergePatch(doc, patch.Document)
+		if err != nil {
+			return domain.RealState{}, customerrors.BadRequest
+		}
+	case ports.JSONPatch:
+		operations, err := jsonpatch.DecodePatch(patch.Document)
+		if err != nil {
+			return domain.RealState{}, customerrors.BadRequest
+		}
+
+		patched, err = operations.Apply(doc)
+		if err != nil {
+			return domain.RealState{}, customerrors.Unprocessable
+		}
+	default:
+		return domain.RealState{}, customerrors.UnsupportedMediaType
+	}
+
+	realState, err := domain.DecodeRealState(bytes.NewReader(patched))
+	if errors.Is(err, domain.ErrMalformedRealState) {
+		return domain.RealState{}, customerrors.Unprocessable
+	}
+
+	return realState, err
+}
+
+func sortValue(field ports.SortField, realState domain.RealState) string {
+	switch field {
+	case ports.SortByRegistration:
+		return strconv.FormatUint(realState.Registration, 10)
+	case ports.SortByPrice:
+		return realState.Price.String()
+	case ports.SortBySize:
+		return strconv.FormatUint(realState.Size, 10)
+	case ports.SortByState:
+		return realState.State
+	default:
+		return ""
+	}
 }
@@ -1,13 +1,22 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
 	"github.com/natanchagas/gin-crud/internal/core/service"
 	"github.com/natanchagas/gin-crud/internal/mocks"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
+	"github.com/natanchagas/gin-crud/internal/pkg/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -21,34 +30,57 @@ func TestCreate(t *testing.T) {
 	testCases := []struct {
 		name      string
 		input     domain.RealState
-		mocking   func(mock *mocks.RealStateRepository, realState domain.RealState) output
+		mocking   func(mock *mocks.RealStateRepository, a *mocks.AuditRepository, realState domain.RealState) output
 		assertion func(t *testing.T, actual, expected output)
 	}{
 		{
-			name: "When real state is valid and repository returns success, should return real state with id",
+			name: "When real state is valid and repository returns success, should return the stored real state",
 			input: domain.RealState{
 				Registration: 987654321,
 				Address:      "456 Elm St",
 				Size:         200,
-				Price:        250000.50,
-				State:        "CA",
+				Price:        domain.NewMoney(25000050),
+				State:        "SP",
 			},
-			mocking: func(m *mocks.RealStateRepository, realState domain.RealState) output {
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, realState domain.RealState) output {
+				created := realState
+				created.Id = 1
+				created.CreatedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+				created.UpdatedAt = created.CreatedAt
+				created.CreatedBy = "jane"
+				created.UpdatedBy = "jane"
 
 				m.
 					On("CreateRealState", mock.AnythingOfType("context.backgroundCtx"), realState).
-					Return(int64(1), nil)
+					Return(created, nil)
 
 				return output{
-					realState: domain.RealState{
-						Id:           1,
-						Registration: 987654321,
-						Address:      "456 Elm St",
-						Size:         200,
-						Price:        250000.50,
-						State:        "CA",
+					realState: created,
+					err:       nil,
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When real state is invalid, should return validation error without calling repository",
+			input: domain.RealState{
+				Registration: 987654321,
+				Address:      "456 Elm St",
+				Size:         200,
+				Price:        domain.NewMoney(0),
+				State:        "XX",
+			},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, realState domain.RealState) output {
+				return output{
+					realState: domain.RealState{},
+					err: domain.ValidationError{
+						Fields: []domain.FieldError{
+							{Field: "price", Rule: domain.RulePositive, Message: "must be greater than zero"},
+							{Field: "state", Rule: domain.RuleState, Message: "must be a brazilian state code"},
+						},
 					},
-					err: nil,
 				}
 			},
 			assertion: func(t *testing.T, actual, expected output) {
@@ -61,14 +93,14 @@ func TestCreate(t *testing.T) {
 				Registration: 987654321,
 				Address:      "456 Elm St",
 				Size:         200,
-				Price:        250000.50,
-				State:        "CA",
+				Price:        domain.NewMoney(25000050),
+				State:        "SP",
 			},
-			mocking: func(m *mocks.RealStateRepository, realState domain.RealState) output {
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, realState domain.RealState) output {
 
 				m.
 					On("CreateRealState", mock.AnythingOfType("context.backgroundCtx"), realState).
-					Return(int64(-1), errors.New("failed to create"))
+					Return(domain.RealState{}, errors.New("failed to create"))
 
 				return output{
 					realState: domain.RealState{},
@@ -86,9 +118,10 @@ func TestCreate(t *testing.T) {
 			ctx := context.Background()
 
 			r := mocks.NewRealStateRepository(t)
-			s := service.NewRealStateService(r)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
 
-			expected := tc.mocking(r, tc.input)
+			expected := tc.mocking(r, a, tc.input)
 
 			var actual output
 			actual.realState, actual.err = s.Create(ctx, tc.input)
@@ -122,8 +155,8 @@ func TestGet(t *testing.T) {
 							Registration: 987654321,
 							Address:      "456 Elm St",
 							Size:         200,
-							Price:        250000.50,
-							State:        "CA",
+							Price:        domain.NewMoney(25000050),
+							State:        "SP",
 						},
 						nil,
 					)
@@ -134,8 +167,8 @@ func TestGet(t *testing.T) {
 						Registration: 987654321,
 						Address:      "456 Elm St",
 						Size:         200,
-						Price:        250000.50,
-						State:        "CA",
+						Price:        domain.NewMoney(25000050),
+						State:        "SP",
 					},
 					err: nil,
 				}
@@ -171,7 +204,8 @@ func TestGet(t *testing.T) {
 			ctx := context.Background()
 
 			r := mocks.NewRealStateRepository(t)
-			s := service.NewRealStateService(r)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
 
 			expected := tc.mocking(r, tc.input)
 
@@ -197,7 +231,7 @@ func TestUpdate(t *testing.T) {
 	testCases := []struct {
 		name      string
 		input     input
-		mocking   func(m *mocks.RealStateRepository, in input) output
+		mocking   func(m *mocks.RealStateRepository, a *mocks.AuditRepository, in input) output
 		assertion func(t *testing.T, actual, expected output)
 	}{
 		{
@@ -207,19 +241,12 @@ func TestUpdate(t *testing.T) {
 					Registration: 987654321,
 					Address:      "456 Elm St",
 					Size:         200,
-					Price:        275000.00,
-					State:        "CA",
+					Price:        domain.NewMoney(27500000),
+					State:        "SP",
 				},
 				id: 1,
 			},
-			mocking: func(m *mocks.RealStateRepository, in input) output {
-				m.
-					On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), in.id).
-					Return(
-						in.realState,
-						nil,
-					)
-
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, in input) output {
 				m.
 					On("UpdateRealState", mock.AnythingOfType("context.backgroundCtx"), in.realState, in.id).
 					Return(
@@ -236,6 +263,32 @@ func TestUpdate(t *testing.T) {
 				assert.Equal(t, expected, actual)
 			},
 		},
+		{
+			name: "When real state is invalid, should return validation error without calling repository",
+			input: input{
+				realState: domain.RealState{
+					Registration: 987654321,
+					Address:      "",
+					Size:         200,
+					Price:        domain.NewMoney(27500000),
+					State:        "SP",
+				},
+				id: 1,
+			},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, in input) output {
+				return output{
+					realState: domain.RealState{},
+					err: domain.ValidationError{
+						Fields: []domain.FieldError{
+							{Field: "address", Rule: domain.RuleRequired, Message: "must not be empty"},
+						},
+					},
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
 		{
 			name: "When real state update fails, should return error",
 			input: input{
@@ -243,19 +296,12 @@ func TestUpdate(t *testing.T) {
 					Registration: 987654321,
 					Address:      "456 Elm St",
 					Size:         200,
-					Price:        275000.00,
-					State:        "CA",
+					Price:        domain.NewMoney(27500000),
+					State:        "SP",
 				},
 				id: 1,
 			},
-			mocking: func(m *mocks.RealStateRepository, in input) output {
-				m.
-					On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), in.id).
-					Return(
-						in.realState,
-						nil,
-					)
-
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, in input) output {
 				m.
 					On("UpdateRealState", mock.AnythingOfType("context.backgroundCtx"), in.realState, in.id).
 					Return(
@@ -273,28 +319,28 @@ func TestUpdate(t *testing.T) {
 			},
 		},
 		{
-			name: "When get fails, should return error",
+			name: "When real state does not exist, should return not found",
 			input: input{
 				realState: domain.RealState{
 					Registration: 987654321,
 					Address:      "456 Elm St",
 					Size:         200,
-					Price:        275000.00,
-					State:        "CA",
+					Price:        domain.NewMoney(27500000),
+					State:        "SP",
 				},
 				id: 1,
 			},
-			mocking: func(m *mocks.RealStateRepository, in input) output {
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, in input) output {
 				m.
-					On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), in.id).
+					On("UpdateRealState", mock.AnythingOfType("context.backgroundCtx"), in.realState, in.id).
 					Return(
 						domain.RealState{},
-						errors.New("get real state failed"),
+						customerrors.NotFound,
 					)
 
 				return output{
 					realState: domain.RealState{},
-					err:       errors.New("get real state failed"),
+					err:       customerrors.NotFound,
 				}
 			},
 			assertion: func(t *testing.T, actual, expected output) {
@@ -309,9 +355,10 @@ func TestUpdate(t *testing.T) {
 				ctx := context.Background()
 
 				r := mocks.NewRealStateRepository(t)
-				s := service.NewRealStateService(r)
+				a := mocks.NewAuditRepository(t)
+				s := service.NewRealStateService(r, a)
 
-				expected := tc.mocking(r, tc.input)
+				expected := tc.mocking(r, a, tc.input)
 
 				var actual output
 				actual.realState, actual.err = s.Update(ctx, tc.input.realState, tc.input.id)
@@ -322,19 +369,199 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestPatch(t *testing.T) {
+	type output struct {
+		realState domain.RealState
+		err       error
+	}
+
+	stored := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(25000050),
+		State:        "SP",
+		CreatedAt:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		CreatedBy:    "jane",
+		UpdatedBy:    "jane",
+		Version:      3,
+	}
+
+	// patched is what the repository is asked to store: the editable fields
+	// of stored after the patch, metadata cleared, and the version read.
+	patched := func(change func(rs *domain.RealState)) domain.RealState {
+		rs := stored.ClearMetadata()
+		rs.Version = stored.Version
+		change(&rs)
+		return rs
+	}
+
+	testCases := []struct {
+		name      string
+		input     ports.RealStatePatch
+		mocking   func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output
+		assertion func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When merge patch changes the price, should keep every other field",
+			input: ports.RealStatePatch{Format: ports.MergePatch, Document: []byte(`{"price":260000}`)},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output {
+				toStore := patched(func(rs *domain.RealState) { rs.Price = domain.NewMoney(26000000) })
+
+				updated := stored
+				updated.Price = toStore.Price
+				updated.Version = 4
+
+				m.On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(stored, nil)
+				m.On("UpdateRealState", mock.AnythingOfType("context.backgroundCtx"), toStore, uint64(1)).Return(updated, nil)
+
+				return output{realState: updated, err: nil}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When json patch tests and replaces, should apply every operation",
+			input: ports.RealStatePatch{
+				Format:   ports.JSONPatch,
+				Document: []byte(`[{"op":"test","path":"/state","value":"SP"},{"op":"replace","path":"/address","value":"1 Main St"}]`),
+				Version:  3,
+			},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output {
+				toStore := patched(func(rs *domain.RealState) { rs.Address = "1 Main St" })
+
+				m.On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(stored, nil)
+				m.On("UpdateRealState", mock.AnythingOfType("context.backgroundCtx"), toStore, uint64(1)).Return(toStore, nil)
+
+				return output{realState: toStore, err: nil}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When patch is based on an older version, should return precondition failed without writing",
+			input: ports.RealStatePatch{Format: ports.MergePatch, Document: []byte(`{"price":260000}`), Version: 2},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output {
+				m.On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(stored, nil)
+
+				return output{realState: domain.RealState{}, err: customerrors.PreconditionFailed}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When patched real state is invalid, should return validation error without writing",
+			input: ports.RealStatePatch{Format: ports.MergePatch, Document: []byte(`{"address":null,"state":"XX"}`)},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output {
+				m.On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(stored, nil)
+
+				return output{
+					realState: domain.RealState{},
+					err: domain.ValidationError{
+						Fields: []domain.FieldError{
+							{Field: "address", Rule: domain.RuleRequired, Message: "must not be empty"},
+							{Field: "state", Rule: domain.RuleState, Message: "must be a brazilian state code"},
+						},
+					},
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When patch sets a field to the wrong type, should return validation error",
+			input: ports.RealStatePatch{Format: ports.MergePatch, Document: []byte(`{"size":"big"}`)},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output {
+				m.On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(stored, nil)
+
+				return output{
+					realState: domain.RealState{},
+					err: domain.ValidationError{
+						Fields: []domain.FieldError{
+							{Field: "size", Rule: domain.RuleType, Message: "must be a non-negative integer"},
+						},
+					},
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When json patch test fails, should return unprocessable",
+			input: ports.RealStatePatch{Format: ports.JSONPatch, Document: []byte(`[{"op":"test","path":"/state","value":"RJ"}]`)},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output {
+				m.On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(stored, nil)
+
+				return output{realState: domain.RealState{}, err: customerrors.Unprocessable}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When json patch is malformed, should return bad request",
+			input: ports.RealStatePatch{Format: ports.JSONPatch, Document: []byte(`{"op":"replace"}`)},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output {
+				m.On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(stored, nil)
+
+				return output{realState: domain.RealState{}, err: customerrors.BadRequest}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When real state does not exist, should return not found",
+			input: ports.RealStatePatch{Format: ports.MergePatch, Document: []byte(`{"price":260000}`)},
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository) output {
+				m.On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(domain.RealState{}, customerrors.NotFound)
+
+				return output{realState: domain.RealState{}, err: customerrors.NotFound}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := mocks.NewRealStateRepository(t)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
+
+			expected := tc.mocking(r, a)
+
+			var actual output
+			actual.realState, actual.err = s.Patch(ctx, tc.input, 1)
+
+			tc.assertion(t, actual, expected)
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	testCases := []struct {
 		name      string
 		input     uint64
-		mocking   func(m *mocks.RealStateRepository, id uint64) error
+		mocking   func(m *mocks.RealStateRepository, a *mocks.AuditRepository, id uint64) error
 		assertion func(t *testing.T, actual, expected error)
 	}{
 		{
 			name:  "When real state exists, should delete and return nil",
 			input: 1,
-			mocking: func(m *mocks.RealStateRepository, id uint64) error {
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, id uint64) error {
 				m.
-					On("DeleteRealState", mock.AnythingOfType("context.backgroundCtx"), id).
+					On("DeleteRealState", mock.AnythingOfType("context.backgroundCtx"), id, uint64(3)).
 					Return(nil)
 
 				return nil
@@ -343,12 +570,40 @@ func TestDelete(t *testing.T) {
 				assert.Equal(t, expected, actual)
 			},
 		},
+		{
+			name:  "When real state does not exist, should return not found",
+			input: 1,
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, id uint64) error {
+				m.
+					On("DeleteRealState", mock.AnythingOfType("context.backgroundCtx"), id, uint64(3)).
+					Return(customerrors.NotFound)
+
+				return customerrors.NotFound
+			},
+			assertion: func(t *testing.T, actual, expected error) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When real state changed since it was read, should return precondition failed",
+			input: 1,
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, id uint64) error {
+				m.
+					On("DeleteRealState", mock.AnythingOfType("context.backgroundCtx"), id, uint64(3)).
+					Return(customerrors.PreconditionFailed)
+
+				return customerrors.PreconditionFailed
+			},
+			assertion: func(t *testing.T, actual, expected error) {
+				assert.Equal(t, expected, actual)
+			},
+		},
 		{
 			name:  "When real state exists, but delete fails should return error",
 			input: 1,
-			mocking: func(m *mocks.RealStateRepository, id uint64) error {
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, id uint64) error {
 				m.
-					On("DeleteRealState", mock.AnythingOfType("context.backgroundCtx"), id).
+					On("DeleteRealState", mock.AnythingOfType("context.backgroundCtx"), id, uint64(3)).
 					Return(errors.New("delete real state failed"))
 
 				return errors.New("delete real state failed")
@@ -364,11 +619,706 @@ func TestDelete(t *testing.T) {
 			ctx := context.Background()
 
 			r := mocks.NewRealStateRepository(t)
-			s := service.NewRealStateService(r)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
 
-			expected := tc.mocking(r, tc.input)
+			expected := tc.mocking(r, a, tc.input)
+
+			actual := s.Delete(ctx, tc.input, 3)
+
+			tc.assertion(t, actual, expected)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	type output struct {
+		realState domain.RealState
+		err       error
+	}
+
+	restored := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(25000000),
+		State:        "SP",
+		Version:      5,
+	}
+
+	testCases := []struct {
+		name      string
+		input     uint64
+		mocking   func(m *mocks.RealStateRepository, a *mocks.AuditRepository, id uint64) output
+		assertion func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When real state was deleted, should restore and return it",
+			input: 1,
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, id uint64) output {
+				m.
+					On("RestoreRealState", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(restored, nil)
+
+				return output{realState: restored}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When no deleted real state has the id, should return not found",
+			input: 1,
+			mocking: func(m *mocks.RealStateRepository, a *mocks.AuditRepository, id uint64) output {
+				m.
+					On("RestoreRealState", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(domain.RealState{}, customerrors.NotFound)
+
+				return output{err: customerrors.NotFound}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := mocks.NewRealStateRepository(t)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
+
+			expected := tc.mocking(r, a, tc.input)
+
+			var actual output
+			actual.realState, actual.err = s.Restore(ctx, tc.input)
+
+			tc.assertion(t, actual, expected)
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	type input struct {
+		ctx       context.Context
+		retention time.Duration
+	}
+
+	type output struct {
+		purged uint64
+		err    error
+	}
+
+	admin := identity.NewContext(context.Background(), identity.Caller{Id: "jane", Roles: []string{identity.RoleAdmin}})
+
+	// deletedBefore matches the cutoff the service derives from retention,
+	// allowing for the time the test takes to run.
+	deletedBefore := func(retention time.Duration) any {
+		from := time.Now().Add(-retention)
+
+		return mock.MatchedBy(func(cutoff time.Time) bool {
+			return !cutoff.Before(from) && !cutoff.After(time.Now().Add(-retention))
+		})
+	}
+
+	testCases := []struct {
+		name      string
+		input     input
+		mocking   func(m *mocks.RealStateRepository, in input) output
+		assertion func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When caller is an admin, should purge real states deleted before the retention",
+			input: input{ctx: admin, retention: 7 * 24 * time.Hour},
+			mocking: func(m *mocks.RealStateRepository, in input) output {
+				m.
+					On("PurgeRealStates", mock.Anything, deletedBefore(in.retention)).
+					Return(uint64(3), nil)
 
-			actual := s.Delete(ctx, tc.input)
+				return output{purged: 3}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When retention is not configured, should use the default",
+			input: input{ctx: admin},
+			mocking: func(m *mocks.RealStateRepository, in input) output {
+				m.
+					On("PurgeRealStates", mock.Anything, deletedBefore(service.DefaultPurgeRetention)).
+					Return(uint64(0), nil)
+
+				return output{}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When caller is not an admin, should return forbidden without purging",
+			input: input{ctx: identity.NewContext(context.Background(), identity.Caller{Id: "john", Roles: []string{"viewer"}})},
+			mocking: func(m *mocks.RealStateRepository, in input) output {
+				return output{err: customerrors.Forbidden}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When caller is anonymous, should return forbidden without purging",
+			input: input{ctx: context.Background()},
+			mocking: func(m *mocks.RealStateRepository, in input) output {
+				return output{err: customerrors.Forbidden}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When purge fails, should return error",
+			input: input{ctx: admin, retention: time.Hour},
+			mocking: func(m *mocks.RealStateRepository, in input) output {
+				m.
+					On("PurgeRealStates", mock.Anything, deletedBefore(in.retention)).
+					Return(uint64(0), customerrors.Internal)
+
+				return output{err: customerrors.Internal}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := mocks.NewRealStateRepository(t)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
+			s.PurgeRetention = tc.input.retention
+
+			expected := tc.mocking(r, tc.input)
+
+			var actual output
+			actual.purged, actual.err = s.Purge(tc.input.ctx)
+
+			tc.assertion(t, actual, expected)
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	type output struct {
+		page ports.AuditPage
+		err  error
+	}
+
+	entries := []domain.AuditEntry{
+		{
+			Id:          2,
+			RealStateId: 42,
+			Operation:   domain.AuditUpdate,
+			Actor:       "john",
+			At:          time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC),
+			Changes: []domain.FieldChange{
+				{Field: "price", Before: json.RawMessage(`250000.00`), After: json.RawMessage(`260000.00`)},
+			},
+		},
+		{
+			Id:          1,
+			RealStateId: 42,
+			Operation:   domain.AuditCreate,
+			Actor:       "jane",
+			At:          time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			Changes: []domain.FieldChange{
+				{Field: "price", After: json.RawMessage(`250000.00`)},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		input     ports.Page
+		mocking   func(a *mocks.AuditRepository, page ports.Page) output
+		assertion func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When real state has changes, should return the page with the total",
+			input: ports.Page{Limit: 2, Offset: 0},
+			mocking: func(a *mocks.AuditRepository, page ports.Page) output {
+				a.
+					On("ListAudit", mock.AnythingOfType("context.backgroundCtx"), uint64(42), page).
+					Return(entries, uint64(5), nil)
+
+				return output{
+					page: ports.AuditPage{Entries: entries, Total: 5, Page: page},
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When audit cannot be read, should return error",
+			input: ports.Page{Limit: 20, Offset: 0},
+			mocking: func(a *mocks.AuditRepository, page ports.Page) output {
+				a.
+					On("ListAudit", mock.AnythingOfType("context.backgroundCtx"), uint64(42), page).
+					Return(nil, uint64(0), customerrors.Internal)
+
+				return output{err: customerrors.Internal}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := mocks.NewRealStateRepository(t)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
+
+			expected := tc.mocking(a, tc.input)
+
+			var actual output
+			actual.page, actual.err = s.History(ctx, 42, tc.input)
+
+			tc.assertion(t, actual, expected)
+		})
+	}
+}
+
+func TestPrices(t *testing.T) {
+	type output struct {
+		history domain.PriceHistory
+		err     error
+	}
+
+	stored := domain.RealState{
+		Id:           1,
+		Registration: 987654321,
+		Address:      "456 Elm St",
+		Size:         200,
+		Price:        domain.NewMoney(26000000),
+		State:        "SP",
+		CreatedAt:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Version:      3,
+	}
+
+	points := []domain.PricePoint{
+		{Price: domain.NewMoney(25000000), At: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{Price: domain.NewMoney(26000000), At: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)},
+	}
+
+	testCases := []struct {
+		name      string
+		input     uint64
+		mocking   func(m *mocks.RealStateRepository, id uint64) output
+		assertion func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When prices were recorded, should return them with the change since listing",
+			input: 1,
+			mocking: func(m *mocks.RealStateRepository, id uint64) output {
+				m.
+					On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(stored, nil)
+
+				m.
+					On("ListPricePoints", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(points, nil)
+
+				return output{
+					history: domain.PriceHistory{
+						RealStateId:   1,
+						Prices:        points,
+						ListingPrice:  domain.NewMoney(25000000),
+						CurrentPrice:  domain.NewMoney(26000000),
+						Change:        domain.NewMoney(1000000),
+						ChangePercent: 4,
+					},
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When no price was recorded, should use the current price as listing price",
+			input: 1,
+			mocking: func(m *mocks.RealStateRepository, id uint64) output {
+				m.
+					On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(stored, nil)
+
+				m.
+					On("ListPricePoints", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(nil, nil)
+
+				return output{
+					history: domain.PriceHistory{
+						RealStateId:  1,
+						Prices:       []domain.PricePoint{{Price: stored.Price, At: stored.CreatedAt}},
+						ListingPrice: stored.Price,
+						CurrentPrice: stored.Price,
+						Change:       domain.NewMoney(0),
+					},
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When real state does not exist, should return not found without reading prices",
+			input: 1,
+			mocking: func(m *mocks.RealStateRepository, id uint64) output {
+				m.
+					On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(domain.RealState{}, customerrors.NotFound)
+
+				return output{err: customerrors.NotFound}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When prices cannot be read, should return error",
+			input: 1,
+			mocking: func(m *mocks.RealStateRepository, id uint64) output {
+				m.
+					On("GetRealState", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(stored, nil)
+
+				m.
+					On("ListPricePoints", mock.AnythingOfType("context.backgroundCtx"), id).
+					Return(nil, customerrors.Internal)
+
+				return output{err: customerrors.Internal}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := mocks.NewRealStateRepository(t)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
+
+			expected := tc.mocking(r, tc.input)
+
+			var actual output
+			actual.history, actual.err = s.Prices(ctx, tc.input)
+
+			tc.assertion(t, actual, expected)
+		})
+	}
+}
+
+func TestPricesLogsInternalError(t *testing.T) {
+	r := mocks.NewRealStateRepository(t)
+	r.On("GetRealState", mock.Anything, uint64(1)).Return(domain.RealState{Id: 1}, nil)
+	r.On("ListPricePoints", mock.Anything, uint64(1)).Return([]domain.PricePoint{
+		{Price: domain.NewMoney(1)},
+		{Price: domain.NewMoney(math.MinInt64)},
+	}, nil)
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, nil)).With("request_id", "abc")
+	ctx := logging.NewContext(context.Background(), logger)
+
+	_, err := service.NewRealStateService(r, mocks.NewAuditRepository(t)).Prices(ctx, 1)
+
+	assert.Equal(t, customerrors.Internal, err)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "abc", record["request_id"])
+	assert.Equal(t, "Prices", record["operation"])
+	assert.Equal(t, domain.ErrMoneyOverflow.Error(), record["error"])
+}
+
+func TestList(t *testing.T) {
+	type output struct {
+		page ports.RealStatePage
+		err  error
+	}
+
+	testCases := []struct {
+		name      string
+		input     ports.Page
+		mocking   func(m *mocks.RealStateRepository, page ports.Page) output
+		assertion func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When repository returns real states, should return them with total and page",
+			input: ports.Page{Limit: 1, Offset: 0},
+			mocking: func(m *mocks.RealStateRepository, page ports.Page) output {
+				realStates := []domain.RealState{
+					{
+						Id:           1,
+						Registration: 987654321,
+						Address:      "456 Elm St",
+						Size:         200,
+						Price:        domain.NewMoney(25000050),
+						State:        "SP",
+					},
+				}
+
+				m.
+					On("ListRealStates", mock.AnythingOfType("context.backgroundCtx"), ports.RealStateQuery{Page: page}).
+					Return(realStates, uint64(2), nil)
+
+				return output{
+					page: ports.RealStatePage{
+						RealStates: realStates,
+						Total:      2,
+						Page:       page,
+					},
+					err: nil,
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When repository fails, should return error",
+			input: ports.Page{Limit: 1, Offset: 0},
+			mocking: func(m *mocks.RealStateRepository, page ports.Page) output {
+				m.
+					On("ListRealStates", mock.AnythingOfType("context.backgroundCtx"), ports.RealStateQuery{Page: page}).
+					Return(nil, uint64(0), errors.New("list real states failed"))
+
+				return output{
+					page: ports.RealStatePage{},
+					err:  errors.New("list real states failed"),
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := mocks.NewRealStateRepository(t)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
+
+			expected := tc.mocking(r, tc.input)
+
+			var actual output
+			actual.page, actual.err = s.List(ctx, tc.input)
+
+			tc.assertion(t, actual, expected)
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	type output struct {
+		page ports.RealStatePage
+		err  error
+	}
+
+	minPrice := domain.NewMoney(10000000)
+
+	testCases := []struct {
+		name      string
+		input     ports.RealStateQuery
+		mocking   func(m *mocks.RealStateRepository, query ports.RealStateQuery) output
+		assertion func(t *testing.T, actual, expected output)
+	}{
+		{
+			name: "When repository returns matching real states, should return them with total and page",
+			input: ports.RealStateQuery{
+				Filter: ports.RealStateFilter{States: []string{"SP"}, MinPrice: &minPrice},
+				Sort:   []ports.Sort{{Field: ports.SortByPrice, Desc: true}},
+				Page:   ports.Page{Limit: 10, Offset: 0},
+			},
+			mocking: func(m *mocks.RealStateRepository, query ports.RealStateQuery) output {
+				realStates := []domain.RealState{
+					{
+						Id:           1,
+						Registration: 987654321,
+						Address:      "456 Elm St",
+						Size:         200,
+						Price:        domain.NewMoney(25000050),
+						State:        "SP",
+					},
+				}
+
+				m.
+					On("ListRealStates", mock.AnythingOfType("context.backgroundCtx"), query).
+					Return(realStates, uint64(1), nil)
+
+				return output{
+					page: ports.RealStatePage{
+						RealStates: realStates,
+						Total:      1,
+						Page:       query.Page,
+					},
+					err: nil,
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name: "When repository fails, should return error",
+			input: ports.RealStateQuery{
+				Filter: ports.RealStateFilter{States: []string{"SP"}},
+				Page:   ports.Page{Limit: 10, Offset: 0},
+			},
+			mocking: func(m *mocks.RealStateRepository, query ports.RealStateQuery) output {
+				m.
+					On("ListRealStates", mock.AnythingOfType("context.backgroundCtx"), query).
+					Return(nil, uint64(0), errors.New("search real states failed"))
+
+				return output{
+					page: ports.RealStatePage{},
+					err:  errors.New("search real states failed"),
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := mocks.NewRealStateRepository(t)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
+
+			expected := tc.mocking(r, tc.input)
+
+			var actual output
+			actual.page, actual.err = s.Search(ctx, tc.input)
+
+			tc.assertion(t, actual, expected)
+		})
+	}
+}
+
+func TestListAfter(t *testing.T) {
+	type output struct {
+		page ports.RealStateKeysetPage
+		err  error
+	}
+
+	realStates := []domain.RealState{
+		{Id: 3, Registration: 1, Address: "1 Elm St", Size: 100, Price: domain.NewMoney(10000000), State: "SP"},
+		{Id: 1, Registration: 2, Address: "2 Elm St", Size: 200, Price: domain.NewMoney(25000050), State: "SP"},
+		{Id: 2, Registration: 3, Address: "3 Elm St", Size: 300, Price: domain.NewMoney(25000050), State: "RJ"},
+	}
+
+	testCases := []struct {
+		name      string
+		input     ports.KeysetQuery
+		mocking   func(m *mocks.RealStateRepository, query ports.KeysetQuery) output
+		assertion func(t *testing.T, actual, expected output)
+	}{
+		{
+			name:  "When repository returns more rows than the limit, should trim them and point the cursor at the last one",
+			input: ports.KeysetQuery{Sort: ports.Sort{Field: ports.SortByPrice}, Limit: 2},
+			mocking: func(m *mocks.RealStateRepository, query ports.KeysetQuery) output {
+				m.
+					On("ListRealStatesAfter", mock.AnythingOfType("context.backgroundCtx"), ports.KeysetQuery{Sort: query.Sort, Limit: 3}).
+					Return(realStates, nil)
+
+				return output{
+					page: ports.RealStateKeysetPage{
+						RealStates: realStates[:2],
+						Next: &ports.Cursor{
+							Sort:      query.Sort,
+							LastId:    1,
+							LastValue: "250000.50",
+						},
+						Limit: 2,
+					},
+					err: nil,
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When repository returns up to the limit, should not return a cursor",
+			input: ports.KeysetQuery{Sort: ports.Sort{Field: ports.SortById}, Limit: 3},
+			mocking: func(m *mocks.RealStateRepository, query ports.KeysetQuery) output {
+				m.
+					On("ListRealStatesAfter", mock.AnythingOfType("context.backgroundCtx"), ports.KeysetQuery{Sort: query.Sort, Limit: 4}).
+					Return(realStates, nil)
+
+				return output{
+					page: ports.RealStateKeysetPage{
+						RealStates: realStates,
+						Next:       nil,
+						Limit:      3,
+					},
+					err: nil,
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+		{
+			name:  "When repository fails, should return error",
+			input: ports.KeysetQuery{Sort: ports.Sort{Field: ports.SortById}, Limit: 3},
+			mocking: func(m *mocks.RealStateRepository, query ports.KeysetQuery) output {
+				m.
+					On("ListRealStatesAfter", mock.AnythingOfType("context.backgroundCtx"), ports.KeysetQuery{Sort: query.Sort, Limit: 4}).
+					Return(nil, errors.New("list real states failed"))
+
+				return output{
+					page: ports.RealStateKeysetPage{},
+					err:  errors.New("list real states failed"),
+				}
+			},
+			assertion: func(t *testing.T, actual, expected output) {
+				assert.Equal(t, expected, actual)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			r := mocks.NewRealStateRepository(t)
+			a := mocks.NewAuditRepository(t)
+			s := service.NewRealStateService(r, a)
+
+			expected := tc.mocking(r, tc.input)
+
+			var actual output
+			actual.page, actual.err = s.ListAfter(ctx, tc.input)
 
 			tc.assertion(t, actual, expected)
 		})
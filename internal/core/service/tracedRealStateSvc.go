@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/ports"
+	"github.com/natanchagas/gin-crud/internal/pkg/tracing"
+)
+
+const tracerName = "github.com/natanchagas/gin-crud/internal/core/service"
+
+// tracedRealStateService opens a span around every call to the service it
+// wraps, parenting the spans of the repositories the call goes through.
+type tracedRealStateService struct {
+	next   ports.RealStateService
+	tracer trace.Tracer
+}
+
+func NewTracedRealStateService(next ports.RealStateService, tp trace.TracerProvider) *tracedRealStateService {
+	return &tracedRealStateService{next: next, tracer: tp.Tracer(tracerName)}
+}
+
+func (s *tracedRealStateService) Create(ctx context.Context, realState domain.RealState) (domain.RealState, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Create")
+	created, err := s.next.Create(ctx, realState)
+	tracing.EndSpan(span, err)
+	return created, err
+}
+
+func (s *tracedRealStateService) Get(ctx context.Context, id uint64) (domain.RealState, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Get", trace.WithAttributes(tracing.RealStateId(id)))
+	realState, err := s.next.Get(ctx, id)
+	tracing.EndSpan(span, err)
+	return realState, err
+}
+
+func (s *tracedRealStateService) List(ctx context.Context, page ports.Page) (ports.RealStatePage, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.List")
+	result, err := s.next.List(ctx, page)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (s *tracedRealStateService) Search(ctx context.Context, query ports.RealStateQuery) (ports.RealStatePage, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Search")
+	result, err := s.next.Search(ctx, query)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (s *tracedRealStateService) ListAfter(ctx context.Context, query ports.KeysetQuery) (ports.RealStateKeysetPage, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.ListAfter")
+	result, err := s.next.ListAfter(ctx, query)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (s *tracedRealStateService) Update(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Update", trace.WithAttributes(tracing.RealStateId(id)))
+	updated, err := s.next.Update(ctx, realState, id)
+	tracing.EndSpan(span, err)
+	return updated, err
+}
+
+func (s *tracedRealStateService) Patch(ctx context.Context, patch ports.RealStatePatch, id uint64) (domain.RealState, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Patch", trace.WithAttributes(tracing.RealStateId(id)))
+	patched, err := s.next.Patch(ctx, patch, id)
+	tracing.EndSpan(span, err)
+	return patched, err
+}
+
+func (s *tracedRealStateService) Delete(ctx context.Context, id uint64, version uint64) error {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Delete", trace.WithAttributes(tracing.RealStateId(id)))
+	err := s.next.Delete(ctx, id, version)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+func (s *tracedRealStateService) Restore(ctx context.Context, id uint64) (domain.RealState, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Restore", trace.WithAttributes(tracing.RealStateId(id)))
+	realState, err := s.next.Restore(ctx, id)
+	tracing.EndSpan(span, err)
+	return realState, err
+}
+
+func (s *tracedRealStateService) Purge(ctx context.Context) (uint64, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Purge")
+	purged, err := s.next.Purge(ctx)
+	tracing.EndSpan(span, err)
+	return purged, err
+}
+
+func (s *tracedRealStateService) History(ctx context.Context, id uint64, page ports.Page) (ports.AuditPage, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.History", trace.WithAttributes(tracing.RealStateId(id)))
+	history, err := s.next.History(ctx, id, page)
+	tracing.EndSpan(span, err)
+	return history, err
+}
+
+func (s *tracedRealStateService) Prices(ctx context.Context, id uint64) (domain.PriceHistory, error) {
+	ctx, span := s.tracer.Start(ctx, "RealStateService.Prices", trace.WithAttributes(tracing.RealStateId(id)))
+	prices, err := s.next.Prices(ctx, id)
+	tracing.EndSpan(span, err)
+	return prices, err
+}
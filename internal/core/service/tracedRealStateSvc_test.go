@@ -0,0 +1,72 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/natanchagas/gin-crud/internal/core/domain"
+	"github.com/natanchagas/gin-crud/internal/core/service"
+	"github.com/natanchagas/gin-crud/internal/mocks"
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+)
+
+func TestTracedUpdate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		assertions func(t *testing.T, span sdktrace.ReadOnlySpan)
+	}{
+		{
+			name: "When service succeeds, should end a span named after the call with the real state id",
+			assertions: func(t *testing.T, span sdktrace.ReadOnlySpan) {
+				assert.Equal(t, "RealStateService.Update", span.Name())
+				assert.Contains(t, span.Attributes(), attribute.Int64("realstate.id", 1))
+				assert.Equal(t, codes.Unset, span.Status().Code)
+			},
+		},
+		{
+			name: "When service fails, should mark the span as failed and record the error",
+			err:  customerrors.NotFound,
+			assertions: func(t *testing.T, span sdktrace.ReadOnlySpan) {
+				assert.Equal(t, codes.Error, span.Status().Code)
+				assert.Equal(t, customerrors.NotFound.Message, span.Status().Description)
+				assert.Len(t, span.Events(), 1)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			var inner trace.SpanContext
+			next := mocks.NewRealStateService(t)
+			next.
+				On("Update", mock.Anything, domain.RealState{Address: "456 Elm St"}, uint64(1)).
+				Run(func(args mock.Arguments) {
+					inner = trace.SpanContextFromContext(args.Get(0).(context.Context))
+				}).
+				Return(domain.RealState{}, tc.err)
+
+			s := service.NewTracedRealStateService(next, tp)
+			_, err := s.Update(context.Background(), domain.RealState{Address: "456 Elm St"}, 1)
+
+			assert.Equal(t, tc.err, err)
+
+			spans := recorder.Ended()
+			if assert.Len(t, spans, 1) {
+				assert.Equal(t, spans[0].SpanContext(), inner, "the wrapped service must run within the span")
+				tc.assertions(t, spans[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.53.7. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/natanchagas/gin-crud/internal/core/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	ports "github.com/natanchagas/gin-crud/internal/core/ports"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+// ListAudit provides a mock function with given fields: ctx, realStateId, page
+func (_m *AuditRepository) ListAudit(ctx context.Context, realStateId uint64, page ports.Page) ([]domain.AuditEntry, uint64, error) {
+	ret := _m.Called(ctx, realStateId, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAudit")
+	}
+
+	var r0 []domain.AuditEntry
+	var r1 uint64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, ports.Page) ([]domain.AuditEntry, uint64, error)); ok {
+		return rf(ctx, realStateId, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, ports.Page) []domain.AuditEntry); ok {
+		r0 = rf(ctx, realStateId, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, ports.Page) uint64); ok {
+		r1 = rf(ctx, realStateId, page)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, ports.Page) error); ok {
+		r2 = rf(ctx, realStateId, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,4 +1,4 @@
-// Code generated by mockery v2.39.1. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package mocks
 
@@ -7,6 +7,10 @@ import (
 
 	domain "github.com/natanchagas/gin-crud/internal/core/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	ports "github.com/natanchagas/gin-crud/internal/core/ports"
+
+	time "time"
 )
 
 // RealStateRepository is an autogenerated mock type for the RealStateRepository type
@@ -15,22 +19,22 @@ type RealStateRepository struct {
 }
 
 // CreateRealState provides a mock function with given fields: ctx, realState
-func (_m *RealStateRepository) CreateRealState(ctx context.Context, realState domain.RealState) (int64, error) {
+func (_m *RealStateRepository) CreateRealState(ctx context.Context, realState domain.RealState) (domain.RealState, error) {
 	ret := _m.Called(ctx, realState)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateRealState")
 	}
 
-	var r0 int64
+	var r0 domain.RealState
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.RealState) (int64, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RealState) (domain.RealState, error)); ok {
 		return rf(ctx, realState)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domain.RealState) int64); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RealState) domain.RealState); ok {
 		r0 = rf(ctx, realState)
 	} else {
-		r0 = ret.Get(0).(int64)
+		r0 = ret.Get(0).(domain.RealState)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, domain.RealState) error); ok {
@@ -42,17 +46,17 @@ func (_m *RealStateRepository) CreateRealState(ctx context.Context, realState do
 	return r0, r1
 }
 
-// DeleteRealState provides a mock function with given fields: ctx, id
-func (_m *RealStateRepository) DeleteRealState(ctx context.Context, id uint64) error {
-	ret := _m.Called(ctx, id)
+// DeleteRealState provides a mock function with given fields: ctx, id, version
+func (_m *RealStateRepository) DeleteRealState(ctx context.Context, id uint64, version uint64) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteRealState")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -88,6 +92,159 @@ func (_m *RealStateRepository) GetRealState(ctx context.Context, id uint64) (dom
 	return r0, r1
 }
 
+// ListPricePoints provides a mock function with given fields: ctx, id
+func (_m *RealStateRepository) ListPricePoints(ctx context.Context, id uint64) ([]domain.PricePoint, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPricePoints")
+	}
+
+	var r0 []domain.PricePoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]domain.PricePoint, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []domain.PricePoint); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PricePoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRealStates provides a mock function with given fields: ctx, query
+func (_m *RealStateRepository) ListRealStates(ctx context.Context, query ports.RealStateQuery) ([]domain.RealState, uint64, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRealStates")
+	}
+
+	var r0 []domain.RealState
+	var r1 uint64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ports.RealStateQuery) ([]domain.RealState, uint64, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ports.RealStateQuery) []domain.RealState); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.RealState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ports.RealStateQuery) uint64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ports.RealStateQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListRealStatesAfter provides a mock function with given fields: ctx, query
+func (_m *RealStateRepository) ListRealStatesAfter(ctx context.Context, query ports.KeysetQuery) ([]domain.RealState, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRealStatesAfter")
+	}
+
+	var r0 []domain.RealState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ports.KeysetQuery) ([]domain.RealState, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ports.KeysetQuery) []domain.RealState); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.RealState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ports.KeysetQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeRealStates provides a mock function with given fields: ctx, deletedBefore
+func (_m *RealStateRepository) PurgeRealStates(ctx context.Context, deletedBefore time.Time) (uint64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeRealStates")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (uint64, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) uint64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreRealState provides a mock function with given fields: ctx, id
+func (_m *RealStateRepository) RestoreRealState(ctx context.Context, id uint64) (domain.RealState, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreRealState")
+	}
+
+	var r0 domain.RealState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (domain.RealState, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) domain.RealState); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.RealState)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateRealState provides a mock function with given fields: ctx, realState, id
 func (_m *RealStateRepository) UpdateRealState(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error) {
 	ret := _m.Called(ctx, realState, id)
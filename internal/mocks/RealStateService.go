@@ -1,4 +1,4 @@
-// Code generated by mockery v2.39.1. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package mocks
 
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/natanchagas/gin-crud/internal/core/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	ports "github.com/natanchagas/gin-crud/internal/core/ports"
 )
 
 // RealStateService is an autogenerated mock type for the RealStateService type
@@ -42,17 +44,17 @@ func (_m *RealStateService) Create(ctx context.Context, realState domain.RealSta
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *RealStateService) Delete(ctx context.Context, id uint64) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *RealStateService) Delete(ctx context.Context, id uint64, version uint64) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -88,6 +90,230 @@ func (_m *RealStateService) Get(ctx context.Context, id uint64) (domain.RealStat
 	return r0, r1
 }
 
+// History provides a mock function with given fields: ctx, id, page
+func (_m *RealStateService) History(ctx context.Context, id uint64, page ports.Page) (ports.AuditPage, error) {
+	ret := _m.Called(ctx, id, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 ports.AuditPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, ports.Page) (ports.AuditPage, error)); ok {
+		return rf(ctx, id, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, ports.Page) ports.AuditPage); ok {
+		r0 = rf(ctx, id, page)
+	} else {
+		r0 = ret.Get(0).(ports.AuditPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, ports.Page) error); ok {
+		r1 = rf(ctx, id, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, page
+func (_m *RealStateService) List(ctx context.Context, page ports.Page) (ports.RealStatePage, error) {
+	ret := _m.Called(ctx, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 ports.RealStatePage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ports.Page) (ports.RealStatePage, error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ports.Page) ports.RealStatePage); ok {
+		r0 = rf(ctx, page)
+	} else {
+		r0 = ret.Get(0).(ports.RealStatePage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ports.Page) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAfter provides a mock function with given fields: ctx, query
+func (_m *RealStateService) ListAfter(ctx context.Context, query ports.KeysetQuery) (ports.RealStateKeysetPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAfter")
+	}
+
+	var r0 ports.RealStateKeysetPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ports.KeysetQuery) (ports.RealStateKeysetPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ports.KeysetQuery) ports.RealStateKeysetPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(ports.RealStateKeysetPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ports.KeysetQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Patch provides a mock function with given fields: ctx, patch, id
+func (_m *RealStateService) Patch(ctx context.Context, patch ports.RealStatePatch, id uint64) (domain.RealState, error) {
+	ret := _m.Called(ctx, patch, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 domain.RealState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ports.RealStatePatch, uint64) (domain.RealState, error)); ok {
+		return rf(ctx, patch, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ports.RealStatePatch, uint64) domain.RealState); ok {
+		r0 = rf(ctx, patch, id)
+	} else {
+		r0 = ret.Get(0).(domain.RealState)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ports.RealStatePatch, uint64) error); ok {
+		r1 = rf(ctx, patch, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Prices provides a mock function with given fields: ctx, id
+func (_m *RealStateService) Prices(ctx context.Context, id uint64) (domain.PriceHistory, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Prices")
+	}
+
+	var r0 domain.PriceHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (domain.PriceHistory, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) domain.PriceHistory); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.PriceHistory)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx
+func (_m *RealStateService) Purge(ctx context.Context) (uint64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (uint64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) uint64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *RealStateService) Restore(ctx context.Context, id uint64) (domain.RealState, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 domain.RealState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (domain.RealState, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) domain.RealState); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.RealState)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, query
+func (_m *RealStateService) Search(ctx context.Context, query ports.RealStateQuery) (ports.RealStatePage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 ports.RealStatePage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ports.RealStateQuery) (ports.RealStatePage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ports.RealStateQuery) ports.RealStatePage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(ports.RealStatePage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ports.RealStateQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, realState, id
 func (_m *RealStateService) Update(ctx context.Context, realState domain.RealState, id uint64) (domain.RealState, error) {
 	ret := _m.Called(ctx, realState, id)
@@ -0,0 +1,71 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid cursor")
+
+var encoding = base64.RawURLEncoding
+
+// Encode serializes v as JSON and signs it with an HMAC-SHA256 of secret, so
+// clients can hand the token back but cannot forge or alter it.
+func Encode(secret []byte, v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(sign(secret, payload)), nil
+}
+
+func Decode(secret []byte, token string, v any) error {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalid
+	}
+
+	payload, err := encoding.DecodeString(encodedPayload)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if !hmac.Equal(signature, sign(secret, payload)) {
+		return ErrInvalid
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+// NewSecret returns a random secret for signing cursors. Cursors signed with
+// it are only accepted by the same process.
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, sha256.Size)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func sign(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,100 @@
+package cursor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/natanchagas/gin-crud/internal/pkg/cursor"
+	"github.com/stretchr/testify/assert"
+)
+
+type position struct {
+	Id    uint64 `json:"i"`
+	Value string `json:"v"`
+}
+
+func TestCursor(t *testing.T) {
+	secret := []byte("secret")
+
+	testCases := []struct {
+		name      string
+		token     func(t *testing.T) string
+		assertion func(t *testing.T, actual position, err error)
+	}{
+		{
+			name: "When token was encoded with the same secret, should decode it",
+			token: func(t *testing.T) string {
+				token, err := cursor.Encode(secret, position{Id: 42, Value: "250000.5"})
+				assert.NoError(t, err)
+
+				return token
+			},
+			assertion: func(t *testing.T, actual position, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, position{Id: 42, Value: "250000.5"}, actual)
+			},
+		},
+		{
+			name: "When token was encoded with another secret, should fail",
+			token: func(t *testing.T) string {
+				token, err := cursor.Encode([]byte("other"), position{Id: 42})
+				assert.NoError(t, err)
+
+				return token
+			},
+			assertion: func(t *testing.T, actual position, err error) {
+				assert.ErrorIs(t, err, cursor.ErrInvalid)
+			},
+		},
+		{
+			name: "When payload was tampered, should fail",
+			token: func(t *testing.T) string {
+				token, err := cursor.Encode(secret, position{Id: 42})
+				assert.NoError(t, err)
+
+				forged, err := cursor.Encode(secret, position{Id: 1})
+				assert.NoError(t, err)
+
+				payload, _, _ := strings.Cut(forged, ".")
+				_, signature, _ := strings.Cut(token, ".")
+
+				return payload + "." + signature
+			},
+			assertion: func(t *testing.T, actual position, err error) {
+				assert.ErrorIs(t, err, cursor.ErrInvalid)
+			},
+		},
+		{
+			name: "When token is malformed, should fail",
+			token: func(t *testing.T) string {
+				return "not-a-cursor"
+			},
+			assertion: func(t *testing.T, actual position, err error) {
+				assert.ErrorIs(t, err, cursor.ErrInvalid)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual position
+			err := cursor.Decode(secret, tc.token(t), &actual)
+
+			tc.assertion(t, actual, err)
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := cursor.NewSecret()
+	assert.NoError(t, err)
+	second, err := cursor.NewSecret()
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+
+	token, err := cursor.Encode(first, position{Id: 42})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, cursor.Decode(second, token, &position{}), cursor.ErrInvalid)
+}
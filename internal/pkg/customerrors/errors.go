@@ -8,22 +8,43 @@ type ErrorCode string
 
 var (
 	UserRequestError ErrorCode = "BAD_REQUEST"
+	ValidationError  ErrorCode = "VALIDATION_ERROR"
+	AccessDenied     ErrorCode = "FORBIDDEN"
 	ResourceNotFound ErrorCode = "RESOURCE_NOT_FOUND"
+	ResourceConflict ErrorCode = "RESOURCE_CONFLICT"
+	VersionMismatch  ErrorCode = "PRECONDITION_FAILED"
+	VersionRequired  ErrorCode = "PRECONDITION_REQUIRED"
+	UnsupportedMedia ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	PatchNotApplied  ErrorCode = "UNPROCESSABLE_PATCH"
 	ApplicationError ErrorCode = "APPLICATION_ERROR"
 	UnexpectedError  ErrorCode = "UNEXPECTED_ERROR"
 )
 
 var (
-	BadRequest = newError("something is wrong within your request", http.StatusBadRequest, UserRequestError)
-	NotFound   = newError("resource not found", http.StatusNotFound, ResourceNotFound)
-	Internal   = newError("application internal error", http.StatusInternalServerError, ApplicationError)
-	Unexpected = newError("unexpected error", http.StatusInternalServerError, UnexpectedError)
+	BadRequest           = newError("something is wrong within your request", http.StatusBadRequest, UserRequestError)
+	Validation           = newError("one or more fields are invalid", http.StatusBadRequest, ValidationError)
+	Forbidden            = newError("operation is not allowed for the caller", http.StatusForbidden, AccessDenied)
+	NotFound             = newError("resource not found", http.StatusNotFound, ResourceNotFound)
+	Conflict             = newError("resource conflicts with an existing one", http.StatusConflict, ResourceConflict)
+	PreconditionFailed   = newError("resource has changed since it was read", http.StatusPreconditionFailed, VersionMismatch)
+	PreconditionRequired = newError("If-Match header is required", http.StatusPreconditionRequired, VersionRequired)
+	UnsupportedMediaType = newError("content type is not supported", http.StatusUnsupportedMediaType, UnsupportedMedia)
+	Unprocessable        = newError("patch cannot be applied to the resource", http.StatusUnprocessableEntity, PatchNotApplied)
+	Internal             = newError("application internal error", http.StatusInternalServerError, ApplicationError)
+	Unexpected           = newError("unexpected error", http.StatusInternalServerError, UnexpectedError)
 )
 
 type Error struct {
 	StatusCode int
 	ErrorCode  ErrorCode
 	Message    string
+	Details    []Detail `json:"details,omitempty"`
+}
+
+type Detail struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 func newError(message string, statusCode int, errorCode ErrorCode) Error {
@@ -37,3 +58,9 @@ func newError(message string, statusCode int, errorCode ErrorCode) Error {
 func (e Error) Error() string {
 	return e.Message
 }
+
+func (e Error) WithDetails(details ...Detail) Error {
+	e.Details = append([]Detail(nil), details...)
+
+	return e
+}
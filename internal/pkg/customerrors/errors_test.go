@@ -1,6 +1,7 @@
 package customerrors_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -30,6 +31,36 @@ func TestCustomError(t *testing.T) {
 				assert.Equal(t, customerrors.UserRequestError, cerr.ErrorCode)
 			},
 		},
+		{
+			name: "When error is Conflict, should be an customerror",
+			err:  customerrors.Conflict,
+			assertion: func(t *testing.T, err error) {
+				assert.IsType(t, customerrors.Conflict, err)
+
+				cerr, ok := err.(customerrors.Error)
+				if !ok {
+					t.Fail()
+				}
+
+				assert.Equal(t, http.StatusConflict, cerr.StatusCode)
+				assert.Equal(t, customerrors.ResourceConflict, cerr.ErrorCode)
+			},
+		},
+		{
+			name: "When details are attached, should keep status and code without changing the base error",
+			err:  customerrors.Validation.WithDetails(customerrors.Detail{Field: "price", Rule: "positive", Message: "must be greater than zero"}),
+			assertion: func(t *testing.T, err error) {
+				cerr, ok := err.(customerrors.Error)
+				if !ok {
+					t.Fail()
+				}
+
+				assert.Equal(t, http.StatusBadRequest, cerr.StatusCode)
+				assert.Equal(t, customerrors.ValidationError, cerr.ErrorCode)
+				assert.Equal(t, []customerrors.Detail{{Field: "price", Rule: "positive", Message: "must be greater than zero"}}, cerr.Details)
+				assert.Empty(t, customerrors.Validation.Details)
+			},
+		},
 		{
 			name: "When error is not customerror, should be an error",
 			err: fmt.Errorf("some error occurred"),
@@ -50,3 +81,33 @@ func TestCustomError(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      customerrors.Error
+		expected string
+	}{
+		{
+			name:     "When error has no details, should leave them out",
+			err:      customerrors.NotFound,
+			expected: `{"StatusCode":404,"ErrorCode":"RESOURCE_NOT_FOUND","Message":"resource not found"}`,
+		},
+		{
+			name: "When error has details, should write them with lowercase members",
+			err: customerrors.Validation.WithDetails(
+				customerrors.Detail{Field: "price", Rule: "positive", Message: "must be greater than zero"},
+			),
+			expected: `{"StatusCode":400,"ErrorCode":"VALIDATION_ERROR","Message":"one or more fields are invalid","details":[{"field":"price","rule":"positive","message":"must be greater than zero"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.err)
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(b))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package customerrors
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+	problemTypePrefix  = "/problems/"
+)
+
+// Problem is the RFC 7807 representation of an Error. Code and Details are
+// extension members so clients can keep branching on the error code.
+type Problem struct {
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	Status   int       `json:"status"`
+	Detail   string    `json:"detail"`
+	Instance string    `json:"instance,omitempty"`
+	Code     ErrorCode `json:"code"`
+	Details  []Detail  `json:"details,omitempty"`
+}
+
+func (e Error) Problem(instance string) Problem {
+	return Problem{
+		Type:     problemTypePrefix + strings.ToLower(strings.ReplaceAll(string(e.ErrorCode), "_", "-")),
+		Title:    http.StatusText(e.StatusCode),
+		Status:   e.StatusCode,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.ErrorCode,
+		Details:  e.Details,
+	}
+}
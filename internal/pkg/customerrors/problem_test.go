@@ -0,0 +1,56 @@
+package customerrors_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/natanchagas/gin-crud/internal/pkg/customerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblem(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      customerrors.Error
+		instance string
+		expected customerrors.Problem
+	}{
+		{
+			name:     "When error is NotFound, should derive type and title from code and status",
+			err:      customerrors.NotFound,
+			instance: "/realstate/1",
+			expected: customerrors.Problem{
+				Type:     "/problems/resource-not-found",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "resource not found",
+				Instance: "/realstate/1",
+				Code:     customerrors.ResourceNotFound,
+			},
+		},
+		{
+			name: "When error has details, should carry them as an extension member",
+			err: customerrors.Validation.WithDetails(
+				customerrors.Detail{Field: "price", Rule: "positive", Message: "must be greater than zero"},
+			),
+			instance: "/realstate/",
+			expected: customerrors.Problem{
+				Type:     "/problems/validation-error",
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   "one or more fields are invalid",
+				Instance: "/realstate/",
+				Code:     customerrors.ValidationError,
+				Details: []customerrors.Detail{
+					{Field: "price", Rule: "positive", Message: "must be greater than zero"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.err.Problem(tc.instance))
+		})
+	}
+}
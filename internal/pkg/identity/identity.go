@@ -0,0 +1,48 @@
+package identity
+
+import (
+	"context"
+	"slices"
+)
+
+// Anonymous is recorded as the actor when a request carries no identity.
+const Anonymous = "anonymous"
+
+// RoleAdmin is required for maintenance operations such as purging.
+const RoleAdmin = "admin"
+
+type contextKey struct{}
+
+type Caller struct {
+	Id    string
+	Roles []string
+}
+
+func (c Caller) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+func NewContext(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, contextKey{}, caller)
+}
+
+func FromContext(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(contextKey{}).(Caller)
+	return caller, ok
+}
+
+// Actor returns the id to stamp on writes made on behalf of ctx.
+func Actor(ctx context.Context) string {
+	if caller, ok := FromContext(ctx); ok && caller.Id != "" {
+		return caller.Id
+	}
+
+	return Anonymous
+}
+
+// HasRole reports whether the caller of ctx was granted role.
+func HasRole(ctx context.Context, role string) bool {
+	caller, ok := FromContext(ctx)
+
+	return ok && caller.HasRole(role)
+}
@@ -0,0 +1,69 @@
+package identity_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/natanchagas/gin-crud/internal/pkg/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "When context carries a caller, should return its id",
+			ctx:      identity.NewContext(context.Background(), identity.Caller{Id: "jane"}),
+			expected: "jane",
+		},
+		{
+			name:     "When caller id is empty, should return anonymous",
+			ctx:      identity.NewContext(context.Background(), identity.Caller{}),
+			expected: identity.Anonymous,
+		},
+		{
+			name:     "When context carries no caller, should return anonymous",
+			ctx:      context.Background(),
+			expected: identity.Anonymous,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, identity.Actor(tc.ctx))
+		})
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		expected bool
+	}{
+		{
+			name:     "When caller was granted the role, should return true",
+			ctx:      identity.NewContext(context.Background(), identity.Caller{Id: "jane", Roles: []string{"viewer", identity.RoleAdmin}}),
+			expected: true,
+		},
+		{
+			name:     "When caller was granted other roles, should return false",
+			ctx:      identity.NewContext(context.Background(), identity.Caller{Id: "john", Roles: []string{"viewer"}}),
+			expected: false,
+		},
+		{
+			name:     "When context carries no caller, should return false",
+			ctx:      context.Background(),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, identity.HasRole(tc.ctx, identity.RoleAdmin))
+		})
+	}
+}
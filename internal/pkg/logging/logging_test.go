@@ -0,0 +1,79 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/natanchagas/gin-crud/internal/pkg/logging"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name       string
+		level      string
+		format     string
+		assertions func(t *testing.T, logger *slog.Logger, out *bytes.Buffer, err error)
+	}{
+		{
+			name: "When level and format are empty, should write info records as JSON",
+			assertions: func(t *testing.T, logger *slog.Logger, out *bytes.Buffer, err error) {
+				assert.NoError(t, err)
+
+				logger.Debug("hidden")
+				logger.Info("shown", "request_id", "abc")
+
+				var record map[string]any
+				assert.NoError(t, json.Unmarshal(out.Bytes(), &record))
+				assert.Equal(t, "shown", record["msg"])
+				assert.Equal(t, "abc", record["request_id"])
+			},
+		},
+		{
+			name:   "When text format and debug level are chosen, should write debug records as text",
+			level:  "DEBUG",
+			format: "text",
+			assertions: func(t *testing.T, logger *slog.Logger, out *bytes.Buffer, err error) {
+				assert.NoError(t, err)
+
+				logger.Debug("shown")
+
+				assert.Contains(t, out.String(), "level=DEBUG msg=shown")
+			},
+		},
+		{
+			name:  "When level is unknown, should return error",
+			level: "loud",
+			assertions: func(t *testing.T, logger *slog.Logger, out *bytes.Buffer, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:   "When format is unknown, should return error",
+			format: "xml",
+			assertions: func(t *testing.T, logger *slog.Logger, out *bytes.Buffer, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger, err := logging.New(&out, tc.level, tc.format)
+
+			tc.assertions(t, logger, &out, err)
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	assert.Same(t, logger, logging.FromContext(logging.NewContext(context.Background(), logger)))
+	assert.Same(t, slog.Default(), logging.FromContext(context.Background()))
+}
@@ -0,0 +1,23 @@
+// Package tracing holds the span helpers the traced decorators share.
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RealStateId is the attribute naming the real state a span is about.
+func RealStateId(id uint64) attribute.KeyValue {
+	return attribute.Int64("realstate.id", int64(id))
+}
+
+// EndSpan marks span as failed when err is set, then ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
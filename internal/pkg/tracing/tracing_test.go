@@ -0,0 +1,50 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/natanchagas/gin-crud/internal/pkg/tracing"
+)
+
+func TestEndSpan(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		status codes.Code
+		events int
+	}{
+		{
+			name:   "When call succeeds, should end the span unset",
+			status: codes.Unset,
+		},
+		{
+			name:   "When call fails, should mark the span as failed and record the error",
+			err:    errors.New("boom"),
+			status: codes.Error,
+			events: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			_, span := tp.Tracer("test").Start(context.Background(), "call")
+			tracing.EndSpan(span, tc.err)
+
+			spans := recorder.Ended()
+			if assert.Len(t, spans, 1) {
+				assert.Equal(t, tc.status, spans[0].Status().Code)
+				assert.Len(t, spans[0].Events(), tc.events)
+			}
+		})
+	}
+}